@@ -10,12 +10,16 @@ import (
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/sudeeya/avito-assignment/internal/config"
 	grpc_v1 "github.com/sudeeya/avito-assignment/internal/controller/grpc/v1"
 	http_v1 "github.com/sudeeya/avito-assignment/internal/controller/http/v1"
+	"github.com/sudeeya/avito-assignment/internal/gateway"
 	"github.com/sudeeya/avito-assignment/internal/grpcserver"
 	"github.com/sudeeya/avito-assignment/internal/httpserver"
+	"github.com/sudeeya/avito-assignment/internal/repository"
 	"github.com/sudeeya/avito-assignment/internal/repository/postgres"
 	"github.com/sudeeya/avito-assignment/internal/service"
 )
@@ -25,9 +29,11 @@ const (
 )
 
 type App struct {
-	cfg        *config.Config
-	httpServer *http.Server
-	grpcServer *grpc.Server
+	cfg          *config.Config
+	repo         repository.Repository
+	httpServer   *http.Server
+	grpcServer   *grpc.Server
+	healthServer *health.Server
 }
 
 func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
@@ -41,16 +47,39 @@ func NewApp(ctx context.Context, cfg *config.Config) (*App, error) {
 		return nil, fmt.Errorf("creating services: %w", err)
 	}
 
-	router := http_v1.NewRouter(services)
+	gatewayHandler, err := gateway.NewHandler(ctx, cfg.ServerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating gateway: %w", err)
+	}
+
+	router, err := http_v1.NewRouter(services, gatewayHandler)
+	if err != nil {
+		return nil, fmt.Errorf("creating router: %w", err)
+	}
 	httpServer := httpserver.NewServer(cfg.ServerConfig, router)
 
 	pvzServiceServer := grpc_v1.NewPVZServiceServerImplementation(services)
-	grpcServer := grpcserver.NewServer(pvzServiceServer)
+	healthServer := health.NewServer()
+	grpcServer := grpcserver.NewServer(
+		cfg.ServerConfig,
+		pvzServiceServer,
+		healthServer,
+		grpc.ChainUnaryInterceptor(
+			grpc_v1.AuthUnaryInterceptor(services.Auth),
+			grpc_v1.LanguageUnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			grpc_v1.AuthStreamInterceptor(services.Auth),
+			grpc_v1.LanguageStreamInterceptor(),
+		),
+	)
 
 	return &App{
-		cfg:        cfg,
-		httpServer: httpServer,
-		grpcServer: grpcServer,
+		cfg:          cfg,
+		repo:         repo,
+		httpServer:   httpServer,
+		grpcServer:   grpcServer,
+		healthServer: healthServer,
 	}, nil
 }
 
@@ -60,6 +89,9 @@ func (a *App) Run(ctx context.Context) {
 		grpcDone = make(chan struct{})
 	)
 
+	a.checkReadiness(ctx)
+	go a.watchReadiness(ctx)
+
 	go func() {
 		defer close(httpDone)
 
@@ -97,12 +129,71 @@ func (a *App) Run(ctx context.Context) {
 func (a *App) Shutdown(ctx context.Context) {
 	zap.L().Info("Server is shutting down...")
 
-	ctx, cancel := context.WithTimeout(ctx, _shutdownTimeout)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), _shutdownTimeout)
 	defer cancel()
 
+	// Load balancers need time to see NOT_SERVING and stop sending
+	// new calls before the server stops accepting them.
+	a.healthServer.Shutdown()
+
+	grace := time.NewTimer(a.cfg.ServerConfig.ServerHealthGrace)
+	defer grace.Stop()
+
+	select {
+	case <-grace.C:
+	case <-ctx.Done():
+	}
+
 	if err := a.httpServer.Shutdown(ctx); err != nil {
 		zap.S().Errorf("HTTP Server shutdown: %v", err)
 	}
 
-	a.grpcServer.GracefulStop()
+	a.drainGRPC()
+}
+
+// drainGRPC waits for in-flight calls to finish, but no longer than drain timeout.
+// Streams like WatchReceptions may never finish, so they are cut off.
+func (a *App) drainGRPC() {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		a.grpcServer.GracefulStop()
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(a.cfg.ServerConfig.ServerDrainTimeout):
+		zap.L().Warn("gRPC drain timeout exceeded, stopping forcibly")
+		a.grpcServer.Stop()
+		<-stopped
+	}
+}
+
+// watchReadiness periodically reports database availability to health service.
+func (a *App) watchReadiness(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.ServerConfig.ServerHealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.checkReadiness(ctx)
+		}
+	}
+}
+
+func (a *App) checkReadiness(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, a.cfg.ServerConfig.ServerHealthCheckInterval)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	if err := a.repo.Ping(ctx); err != nil {
+		zap.S().Errorf("Pinging database: %v", err)
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	a.healthServer.SetServingStatus("", status)
+	a.healthServer.SetServingStatus(grpc_v1.PVZService_ServiceDesc.ServiceName, status)
 }
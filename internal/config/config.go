@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -17,9 +18,27 @@ type LogConfig struct {
 }
 
 type ServerConfig struct {
-	ServerHTTPPort  int    `env:"SERVER_HTTP_PORT,required"`
-	ServerGRPCPort  int    `env:"SERVER_GRPC_PORT,required"`
-	ServerSecretKey string `env:"SERVER_SECRET_KEY,required"`
+	ServerHTTPPort        int           `env:"SERVER_HTTP_PORT,required"`
+	ServerGRPCPort        int           `env:"SERVER_GRPC_PORT,required"`
+	ServerAccessTokenTTL  time.Duration `env:"SERVER_ACCESS_TOKEN_TTL" envDefault:"15m"`
+	ServerRefreshTokenTTL time.Duration `env:"SERVER_REFRESH_TOKEN_TTL" envDefault:"720h"`
+	ServerMaxPageSize     int           `env:"SERVER_MAX_PAGE_SIZE" envDefault:"10"`
+
+	ServerGRPCReflection      bool          `env:"SERVER_GRPC_REFLECTION" envDefault:"false"`
+	ServerHealthCheckInterval time.Duration `env:"SERVER_HEALTH_CHECK_INTERVAL" envDefault:"5s"`
+	ServerHealthGrace         time.Duration `env:"SERVER_HEALTH_GRACE" envDefault:"2s"`
+	ServerDrainTimeout        time.Duration `env:"SERVER_DRAIN_TIMEOUT" envDefault:"10s"`
+
+	// Current signing key. HS256 uses ServerSecretKey,
+	// RS256 and EdDSA use private key from ServerPrivateKeyFile.
+	ServerSigningAlgorithm string `env:"SERVER_SIGNING_ALGORITHM" envDefault:"HS256"`
+	ServerSigningKeyID     string `env:"SERVER_SIGNING_KEY_ID" envDefault:"default"`
+	ServerSecretKey        string `env:"SERVER_SECRET_KEY"`
+	ServerPrivateKeyFile   string `env:"SERVER_PRIVATE_KEY_FILE"`
+
+	// Previous keys are used only to verify tokens, format is "kid:value,kid:value".
+	ServerPreviousSecretKeys     map[string]string `env:"SERVER_PREVIOUS_SECRET_KEYS"`
+	ServerPreviousPublicKeyFiles map[string]string `env:"SERVER_PREVIOUS_PUBLIC_KEY_FILES"`
 }
 
 type DBConfig struct {
@@ -0,0 +1,131 @@
+package v1
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+// Domain of ErrorInfo details attached to errors.
+const _errorDomain = "pvz.avito-assignment"
+
+// Error reasons are stable, so clients can branch on them.
+const (
+	_reasonInvalidRequest         = "INVALID_REQUEST"
+	_reasonUnauthenticated        = "UNAUTHENTICATED"
+	_reasonAccessDenied           = "ACCESS_DENIED"
+	_reasonPVZNotFound            = "PVZ_NOT_FOUND"
+	_reasonInvalidCursor          = "INVALID_CURSOR"
+	_reasonInvalidPageSize        = "INVALID_PAGE_SIZE"
+	_reasonInvalidLocation        = "INVALID_LOCATION"
+	_reasonInvalidWorkingHours    = "INVALID_WORKING_HOURS"
+	_reasonInvalidCapacity        = "INVALID_CAPACITY"
+	_reasonUnsupportedPVZStatus   = "UNSUPPORTED_PVZ_STATUS"
+	_reasonPVZNotActive           = "PVZ_NOT_ACTIVE"
+	_reasonUnsupportedCity        = "UNSUPPORTED_CITY"
+	_reasonUnsupportedProductType = "UNSUPPORTED_PRODUCT_TYPE"
+	_reasonReceptionInProgress    = "RECEPTION_IN_PROGRESS"
+	_reasonUnsupportedStatus      = "UNSUPPORTED_RECEPTION_STATUS"
+	_reasonNoReceptionInProgress  = "NO_RECEPTION_IN_PROGRESS"
+	_reasonReceptionIsEmpty       = "RECEPTION_IS_EMPTY"
+	_reasonInvalidProductCount    = "INVALID_PRODUCT_COUNT"
+	_reasonNotEnoughProducts      = "NOT_ENOUGH_PRODUCTS"
+	_reasonSerialNumberRequired   = "SERIAL_NUMBER_REQUIRED"
+	_reasonWeightRequired         = "WEIGHT_REQUIRED"
+	_reasonInvalidWeight          = "INVALID_WEIGHT"
+	_reasonProductTooHeavy        = "PRODUCT_TOO_HEAVY"
+	_reasonInternal               = "INTERNAL"
+)
+
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string
+	field  string // Request field that caused the error, if any.
+}
+
+var _errorMappings = []errorMapping{
+	{service.ErrPVZNotFound, codes.NotFound, _reasonPVZNotFound, ""},
+	{service.ErrInvalidCursor, codes.InvalidArgument, _reasonInvalidCursor, "cursor"},
+	{service.ErrInvalidPageSize, codes.InvalidArgument, _reasonInvalidPageSize, "limit"},
+	{service.ErrInvalidLocation, codes.InvalidArgument, _reasonInvalidLocation, "location"},
+	{service.ErrInvalidWorkingHours, codes.InvalidArgument, _reasonInvalidWorkingHours, "working_hours"},
+	{service.ErrInvalidCapacity, codes.InvalidArgument, _reasonInvalidCapacity, "capacity"},
+	{service.ErrUnsupportedPVZStatus, codes.InvalidArgument, _reasonUnsupportedPVZStatus, "status"},
+	{service.ErrPVZNotActive, codes.FailedPrecondition, _reasonPVZNotActive, ""},
+	{service.ErrUnsupportedCity, codes.InvalidArgument, _reasonUnsupportedCity, "city"},
+	{service.ErrUnsupportedProductType, codes.InvalidArgument, _reasonUnsupportedProductType, "type"},
+	{service.ErrReceptionInProgress, codes.FailedPrecondition, _reasonReceptionInProgress, ""},
+	{service.ErrUnsupportedReceptionStatus, codes.InvalidArgument, _reasonUnsupportedStatus, "status"},
+	{service.ErrNoReceptionInProgress, codes.FailedPrecondition, _reasonNoReceptionInProgress, ""},
+	{service.ErrReceptionIsEmpty, codes.FailedPrecondition, _reasonReceptionIsEmpty, ""},
+	{service.ErrInvalidProductCount, codes.InvalidArgument, _reasonInvalidProductCount, "count"},
+	{service.ErrNotEnoughProducts, codes.FailedPrecondition, _reasonNotEnoughProducts, ""},
+	{service.ErrSerialNumberRequired, codes.InvalidArgument, _reasonSerialNumberRequired, "serial_number"},
+	{service.ErrWeightRequired, codes.InvalidArgument, _reasonWeightRequired, "weight"},
+	{service.ErrInvalidWeight, codes.InvalidArgument, _reasonInvalidWeight, "weight"},
+	{service.ErrProductTooHeavy, codes.InvalidArgument, _reasonProductTooHeavy, "weight"},
+}
+
+// toStatusError translates service errors to gRPC status errors.
+// Unknown errors are reported as Internal without leaking their text.
+func toStatusError(err error) error {
+	for _, m := range _errorMappings {
+		if errors.Is(err, m.err) {
+			return newStatusError(m.code, m.reason, m.err.Error(), m.field)
+		}
+	}
+
+	zap.S().Errorf("Handling gRPC call: %v", err)
+
+	return newStatusError(codes.Internal, _reasonInternal, "internal error", "")
+}
+
+// invalidArgument reports invalid request field.
+func invalidArgument(field, description string) error {
+	return newStatusError(codes.InvalidArgument, _reasonInvalidRequest, description, field)
+}
+
+// unauthenticated reports missing or invalid token.
+func unauthenticated(message string) error {
+	return newStatusError(codes.Unauthenticated, _reasonUnauthenticated, message, "")
+}
+
+// accessDenied reports that role of the caller is not allowed to call the method.
+func accessDenied() error {
+	return newStatusError(codes.PermissionDenied, _reasonAccessDenied, "access denied", "")
+}
+
+func newStatusError(code codes.Code, reason, message, field string) error {
+	st := status.New(code, message)
+
+	details := []protoadapt.MessageV1{
+		&errdetails.ErrorInfo{
+			Reason: reason,
+			Domain: _errorDomain,
+		},
+	}
+	if field != "" {
+		details = append(details, &errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{
+					Field:       field,
+					Description: message,
+				},
+			},
+		})
+	}
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
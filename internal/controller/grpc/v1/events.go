@@ -0,0 +1,112 @@
+package v1
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+func (p *pvzServiceServerImplementation) WatchReceptions(req *WatchReceptionsRequest, stream grpc.ServerStreamingServer[ReceptionEvent]) error {
+	ctx := stream.Context()
+
+	var pvzID uuid.UUID
+	if req.GetPvzId() != "" {
+		var err error
+		if pvzID, err = uuid.Parse(req.GetPvzId()); err != nil {
+			return invalidArgument("pvz_id", "invalid UUID")
+		}
+	}
+
+	// City may be requested by code or any name, but events
+	// carry default names, so it is compared by default name.
+	city := req.GetCity()
+	if city != "" {
+		cities, err := p.services.City.GetCities(ctx)
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		for _, c := range cities {
+			if slices.Contains(c.LookupKeys(), model.LookupKey(city)) {
+				city = c.Name
+				break
+			}
+		}
+	}
+
+	// Cities of PVZs never change, so they are looked up once per stream.
+	// They are looked up without languages to get default names.
+	cities := make(map[uuid.UUID]string)
+	lookupCtx := service.WithLanguages(ctx, nil)
+
+	for event := range p.services.Events.Subscribe(ctx) {
+		if pvzID != uuid.Nil && event.PVZID != pvzID {
+			continue
+		}
+
+		if city != "" {
+			pvzCity, ok := cities[event.PVZID]
+			if !ok {
+				// Event is skipped rather than breaking the stream, city
+				// is not cached, so it is looked up again for the next event.
+				pvz, err := p.services.PVZ.GetPVZ(lookupCtx, event.PVZID)
+				if err != nil {
+					zap.S().Errorf("Looking up city of PVZ %s: %v", event.PVZID, err)
+					continue
+				}
+
+				pvzCity = pvz.City
+				cities[event.PVZID] = pvzCity
+			}
+
+			if pvzCity != city {
+				continue
+			}
+		}
+
+		if err := stream.Send(eventToProto(event)); err != nil {
+			return err
+		}
+	}
+
+	// Events channel is closed only when the client is gone.
+	return nil
+}
+
+func eventToProto(event model.Event) *ReceptionEvent {
+	res := &ReceptionEvent{
+		Type:       eventTypeToProto(event.Type),
+		PvzId:      event.PVZID.String(),
+		OccurredAt: timestamppb.New(event.OccurredAt),
+	}
+
+	switch event.Type {
+	case model.EventReceptionOpened, model.EventReceptionClosed:
+		res.Reception = receptionToProto(event.Reception)
+	case model.EventProductAdded, model.EventProductRemoved:
+		res.Product = productToProto(event.Product)
+	}
+
+	return res
+}
+
+func eventTypeToProto(eventType string) ReceptionEventType {
+	switch eventType {
+	case model.EventReceptionOpened:
+		return ReceptionEventType_RECEPTION_EVENT_TYPE_RECEPTION_OPENED
+	case model.EventProductAdded:
+		return ReceptionEventType_RECEPTION_EVENT_TYPE_PRODUCT_ADDED
+	case model.EventProductRemoved:
+		return ReceptionEventType_RECEPTION_EVENT_TYPE_PRODUCT_REMOVED
+	case model.EventReceptionClosed:
+		return ReceptionEventType_RECEPTION_EVENT_TYPE_RECEPTION_CLOSED
+	default:
+		return ReceptionEventType_RECEPTION_EVENT_TYPE_UNSPECIFIED
+	}
+}
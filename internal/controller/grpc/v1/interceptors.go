@@ -0,0 +1,134 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+const (
+	_authorizationMetadata = "authorization"
+	_bearer                = "Bearer "
+)
+
+// Accept-Language comes as is from gRPC clients and prefixed from the gateway.
+var _acceptLanguageMetadata = []string{"accept-language", "grpcgateway-accept-language"}
+
+// Roles allowed to call methods. They are the same as for HTTP routes.
+// Methods not listed here are denied.
+var _methodRoles = map[string][]string{
+	PVZService_CreatePVZ_FullMethodName:          {model.RoleModerator},
+	PVZService_GetPVZPagination_FullMethodName:   {model.RoleEmployee, model.RoleModerator},
+	PVZService_GetPVZList_FullMethodName:         {model.RoleEmployee, model.RoleModerator},
+	PVZService_UpdatePVZ_FullMethodName:          {model.RoleModerator},
+	PVZService_CreateReception_FullMethodName:    {model.RoleEmployee},
+	PVZService_CloseLastReception_FullMethodName: {model.RoleEmployee},
+	PVZService_AddProduct_FullMethodName:         {model.RoleEmployee},
+	PVZService_DeleteLastProduct_FullMethodName:  {model.RoleEmployee},
+	PVZService_DeleteLastProducts_FullMethodName: {model.RoleEmployee},
+	PVZService_StreamAddProducts_FullMethodName:  {model.RoleEmployee},
+	PVZService_WatchReceptions_FullMethodName:    {model.RoleEmployee, model.RoleModerator},
+}
+
+func AuthUnaryInterceptor(authService service.Auth) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authService, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func AuthStreamInterceptor(authService service.Auth) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authService, info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          ctx,
+		})
+	}
+}
+
+// LanguageUnaryInterceptor passes languages from Accept-Language metadata to services.
+func LanguageUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withLanguages(ctx), req)
+	}
+}
+
+// LanguageStreamInterceptor passes languages from Accept-Language metadata to services.
+func LanguageStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextStream{
+			ServerStream: ss,
+			ctx:          withLanguages(ss.Context()),
+		})
+	}
+}
+
+// contextStream overrides context of the stream with the one carrying identity or languages.
+type contextStream struct {
+	grpc.ServerStream
+
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
+
+func withLanguages(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range _acceptLanguageMetadata {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return service.WithLanguages(ctx, service.ParseAcceptLanguage(values[0]))
+		}
+	}
+
+	return ctx
+}
+
+func authenticate(ctx context.Context, authService service.Auth, fullMethod string) (context.Context, error) {
+	// Only PVZService requires tokens, standard services like health checking are public.
+	if !strings.HasPrefix(fullMethod, "/"+PVZService_ServiceDesc.ServiceName+"/") {
+		return ctx, nil
+	}
+
+	roles, ok := _methodRoles[fullMethod]
+	if !ok {
+		return nil, accessDenied()
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(_authorizationMetadata)
+	if len(values) == 0 || values[0] == "" {
+		return nil, unauthenticated("empty bearer string")
+	}
+
+	tokenString := strings.TrimPrefix(values[0], _bearer)
+
+	identity, err := authService.VerifyToken(ctx, tokenString)
+	if errors.Is(err, service.ErrCannotVerifyToken) { // Token may be valid, so it is not reported as wrong.
+		return nil, toStatusError(err)
+	} else if err != nil {
+		return nil, unauthenticated("wrong token")
+	}
+
+	if !identity.HasRole(roles...) {
+		return nil, accessDenied()
+	}
+
+	return service.WithIdentity(ctx, identity), nil
+}
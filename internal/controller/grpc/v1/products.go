@@ -0,0 +1,160 @@
+package v1
+
+import (
+	context "context"
+	"errors"
+	"io"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+func (p *pvzServiceServerImplementation) AddProduct(ctx context.Context, req *AddProductRequest) (*AddProductResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	product, err := p.services.Product.AddProduct(ctx, pvzID, model.NewProduct{
+		Type:         req.GetType(),
+		SerialNumber: req.GetSerialNumber(),
+		Weight:       int(req.GetWeight()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &AddProductResponse{
+		Product: productToProto(product),
+	}, nil
+}
+
+func (p *pvzServiceServerImplementation) DeleteLastProduct(ctx context.Context, req *DeleteLastProductRequest) (*DeleteLastProductResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	if err := p.services.Product.DeleteLastProduct(ctx, pvzID); err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &DeleteLastProductResponse{}, nil
+}
+
+func (p *pvzServiceServerImplementation) DeleteLastProducts(ctx context.Context, req *DeleteLastProductsRequest) (*DeleteLastProductsResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	// Only the last product is deleted by default.
+	count := int(req.GetCount())
+	if count == 0 {
+		count = 1
+	}
+
+	products, err := p.services.Product.DeleteLastProducts(ctx, pvzID, count)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	resp := &DeleteLastProductsResponse{
+		Products: make([]*Product, 0, len(products)),
+	}
+	for _, product := range products {
+		resp.Products = append(resp.Products, productToProto(product))
+	}
+
+	return resp, nil
+}
+
+// Products from the stream are added in batches of this size.
+const _addProductsBatchSize = 100
+
+func (p *pvzServiceServerImplementation) StreamAddProducts(stream grpc.ClientStreamingServer[StreamAddProductsRequest, StreamAddProductsResponse]) error {
+	var (
+		res    StreamAddProductsResponse
+		pvzID  uuid.UUID
+		offset int // Index of the first product in the batch.
+		batch  = make([]model.NewProduct, 0, _addProductsBatchSize)
+	)
+
+	// Errors of the whole batch, like unknown PVZ or no reception in progress,
+	// fail the call, rejections report only products which are invalid themselves.
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		products, rejections, err := p.services.Product.AddProducts(stream.Context(), pvzID, batch)
+		if err != nil {
+			return toStatusError(err)
+		}
+
+		for _, product := range products {
+			res.ProductIds = append(res.ProductIds, product.ID.String())
+		}
+
+		for _, rejection := range rejections {
+			res.Rejections = append(res.Rejections, &ProductRejection{
+				Index:  int32(offset + rejection.Index),
+				Type:   rejection.Type,
+				Reason: rejection.Reason,
+			})
+		}
+
+		offset += len(batch)
+		batch = batch[:0]
+
+		return nil
+	}
+
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if pvzID == uuid.Nil {
+			if pvzID, err = uuid.Parse(req.GetPvzId()); err != nil {
+				return invalidArgument("pvz_id", "invalid UUID")
+			}
+		} else if req.GetPvzId() != "" && req.GetPvzId() != pvzID.String() {
+			return invalidArgument("pvz_id", "all products must be added to the same PVZ")
+		}
+
+		batch = append(batch, model.NewProduct{
+			Type:         req.GetType(),
+			SerialNumber: req.GetSerialNumber(),
+			Weight:       int(req.GetWeight()),
+		})
+		if len(batch) == _addProductsBatchSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&res)
+}
+
+func productToProto(product model.Product) *Product {
+	return &Product{
+		Id:           product.ID.String(),
+		ReceptionId:  product.ReceptionID.String(),
+		Datetime:     timestamppb.New(product.Datetime),
+		Type:         product.Type,
+		SerialNumber: product.SerialNumber,
+		Weight:       int32(product.Weight),
+	}
+}
@@ -3,6 +3,7 @@ package v1
 import (
 	context "context"
 
+	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/sudeeya/avito-assignment/internal/model"
@@ -21,25 +22,182 @@ func NewPVZServiceServerImplementation(services *service.Services) *pvzServiceSe
 	}
 }
 
+func (p *pvzServiceServerImplementation) CreatePVZ(ctx context.Context, req *CreatePVZRequest) (*CreatePVZResponse, error) {
+	pvz, err := p.services.PVZ.CreatePVZ(ctx, model.PVZ{
+		City:         req.GetCity(),
+		Address:      req.GetAddress(),
+		Location:     locationFromProto(req.GetLocation()),
+		WorkingHours: workingHoursFromProto(req.GetWorkingHours()),
+		Capacity:     int(req.GetCapacity()),
+		Status:       pvzStatusFromProto(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &CreatePVZResponse{
+		Pvz: pvzToProto(pvz),
+	}, nil
+}
+
+func (p *pvzServiceServerImplementation) UpdatePVZ(ctx context.Context, req *UpdatePVZRequest) (*UpdatePVZResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	pvz, err := p.services.PVZ.UpdatePVZ(ctx, model.PVZ{
+		ID:           pvzID,
+		Address:      req.GetAddress(),
+		Location:     locationFromProto(req.GetLocation()),
+		WorkingHours: workingHoursFromProto(req.GetWorkingHours()),
+		Capacity:     int(req.GetCapacity()),
+		Status:       pvzStatusFromProto(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &UpdatePVZResponse{
+		Pvz: pvzToProto(pvz),
+	}, nil
+}
+
+func (p *pvzServiceServerImplementation) GetPVZPagination(ctx context.Context, req *GetPVZPaginationRequest) (*GetPVZPaginationResponse, error) {
+	// Filters are optional, zero values don't filter.
+	filter := model.PVZFilter{
+		City: req.GetCity(),
+	}
+	if req.StartDate != nil {
+		filter.StartDate = req.GetStartDate().AsTime()
+	}
+	if req.EndDate != nil {
+		filter.EndDate = req.GetEndDate().AsTime()
+	}
+	if req.Status != nil {
+		filter.Status = receptionStatusFromProto(req.GetStatus())
+	}
+
+	page, err := p.services.PVZ.GetPVZPagination(
+		ctx,
+		filter,
+		req.GetCursor(),
+		int(req.GetLimit()),
+		req.GetIncludeTotal(),
+	)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	res := GetPVZPaginationResponse{
+		NextCursor: page.NextCursor,
+	}
+	for _, pvz := range page.Items {
+		res.Pvzs = append(res.Pvzs, pvzToProto(pvz))
+	}
+	if page.Total != nil {
+		total := int32(*page.Total)
+		res.Total = &total
+	}
+
+	return &res, nil
+}
+
 func (p *pvzServiceServerImplementation) GetPVZList(ctx context.Context, req *GetPVZListRequest) (*GetPVZListResponse, error) {
 	var res GetPVZListResponse
 
 	pvzs, err := p.services.PVZ.GetPVZList(ctx)
 	if err != nil {
-		return nil, err
+		return nil, toStatusError(err)
 	}
 
 	for _, pvz := range pvzs {
-		res.Pvzs = append(res.Pvzs, toProto(pvz))
+		res.Pvzs = append(res.Pvzs, pvzToProto(pvz))
 	}
 
 	return &res, nil
 }
 
-func toProto(pvz model.PVZ) *PVZ {
-	return &PVZ{
+func pvzToProto(pvz model.PVZ) *PVZ {
+	res := &PVZ{
 		Id:               pvz.ID.String(),
 		RegistrationDate: timestamppb.New(pvz.RegistrationDate),
 		City:             pvz.City,
+		Address:          pvz.Address,
+		Capacity:         int32(pvz.Capacity),
+		Status:           pvzStatusToProto(pvz.Status),
+	}
+
+	if pvz.Location != nil {
+		res.Location = &Location{
+			Latitude:  pvz.Location.Latitude,
+			Longitude: pvz.Location.Longitude,
+		}
+	}
+
+	for _, hours := range pvz.WorkingHours {
+		res.WorkingHours = append(res.WorkingHours, &WorkingHours{
+			Weekday: int32(hours.Weekday),
+			Opens:   hours.Opens,
+			Closes:  hours.Closes,
+		})
+	}
+
+	for _, reception := range pvz.Receptions {
+		res.Receptions = append(res.Receptions, receptionToProto(reception))
+	}
+
+	return res
+}
+
+func locationFromProto(location *Location) *model.Location {
+	if location == nil {
+		return nil
+	}
+
+	return &model.Location{
+		Latitude:  location.GetLatitude(),
+		Longitude: location.GetLongitude(),
+	}
+}
+
+func workingHoursFromProto(workingHours []*WorkingHours) []model.WorkingHours {
+	res := make([]model.WorkingHours, 0, len(workingHours))
+	for _, hours := range workingHours {
+		res = append(res, model.WorkingHours{
+			Weekday: int(hours.GetWeekday()),
+			Opens:   hours.GetOpens(),
+			Closes:  hours.GetCloses(),
+		})
+	}
+
+	return res
+}
+
+func pvzStatusToProto(status string) PVZStatus {
+	switch status {
+	case model.PVZStatusActive:
+		return PVZStatus_PVZ_STATUS_ACTIVE
+	case model.PVZStatusSuspended:
+		return PVZStatus_PVZ_STATUS_SUSPENDED
+	case model.PVZStatusClosed:
+		return PVZStatus_PVZ_STATUS_CLOSED
+	default:
+		return PVZStatus_PVZ_STATUS_UNSPECIFIED
+	}
+}
+
+// pvzStatusFromProto returns empty status for unspecified one,
+// so service can default it or reject it.
+func pvzStatusFromProto(status PVZStatus) string {
+	switch status {
+	case PVZStatus_PVZ_STATUS_ACTIVE:
+		return model.PVZStatusActive
+	case PVZStatus_PVZ_STATUS_SUSPENDED:
+		return model.PVZStatusSuspended
+	case PVZStatus_PVZ_STATUS_CLOSED:
+		return model.PVZStatusClosed
+	default:
+		return ""
 	}
 }
@@ -12,6 +12,7 @@ import (
 	unsafe "unsafe"
 
 	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 )
@@ -23,6 +24,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Only active PVZ accepts new receptions.
+type PVZStatus int32
+
+const (
+	PVZStatus_PVZ_STATUS_UNSPECIFIED PVZStatus = 0
+	PVZStatus_PVZ_STATUS_ACTIVE      PVZStatus = 1
+	PVZStatus_PVZ_STATUS_SUSPENDED   PVZStatus = 2
+	PVZStatus_PVZ_STATUS_CLOSED      PVZStatus = 3
+)
+
+// Enum value maps for PVZStatus.
+var (
+	PVZStatus_name = map[int32]string{
+		0: "PVZ_STATUS_UNSPECIFIED",
+		1: "PVZ_STATUS_ACTIVE",
+		2: "PVZ_STATUS_SUSPENDED",
+		3: "PVZ_STATUS_CLOSED",
+	}
+	PVZStatus_value = map[string]int32{
+		"PVZ_STATUS_UNSPECIFIED": 0,
+		"PVZ_STATUS_ACTIVE":      1,
+		"PVZ_STATUS_SUSPENDED":   2,
+		"PVZ_STATUS_CLOSED":      3,
+	}
+)
+
+func (x PVZStatus) Enum() *PVZStatus {
+	p := new(PVZStatus)
+	*p = x
+	return p
+}
+
+func (x PVZStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PVZStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_pvz_proto_enumTypes[0].Descriptor()
+}
+
+func (PVZStatus) Type() protoreflect.EnumType {
+	return &file_pvz_proto_enumTypes[0]
+}
+
+func (x PVZStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PVZStatus.Descriptor instead.
+func (PVZStatus) EnumDescriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{0}
+}
+
 type ReceptionStatus int32
 
 const (
@@ -53,11 +107,11 @@ func (x ReceptionStatus) String() string {
 }
 
 func (ReceptionStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_pvz_proto_enumTypes[0].Descriptor()
+	return file_pvz_proto_enumTypes[1].Descriptor()
 }
 
 func (ReceptionStatus) Type() protoreflect.EnumType {
-	return &file_pvz_proto_enumTypes[0]
+	return &file_pvz_proto_enumTypes[1]
 }
 
 func (x ReceptionStatus) Number() protoreflect.EnumNumber {
@@ -66,14 +120,77 @@ func (x ReceptionStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ReceptionStatus.Descriptor instead.
 func (ReceptionStatus) EnumDescriptor() ([]byte, []int) {
-	return file_pvz_proto_rawDescGZIP(), []int{0}
+	return file_pvz_proto_rawDescGZIP(), []int{1}
 }
 
+type ReceptionEventType int32
+
+const (
+	ReceptionEventType_RECEPTION_EVENT_TYPE_UNSPECIFIED      ReceptionEventType = 0
+	ReceptionEventType_RECEPTION_EVENT_TYPE_RECEPTION_OPENED ReceptionEventType = 1
+	ReceptionEventType_RECEPTION_EVENT_TYPE_PRODUCT_ADDED    ReceptionEventType = 2
+	ReceptionEventType_RECEPTION_EVENT_TYPE_PRODUCT_REMOVED  ReceptionEventType = 3
+	ReceptionEventType_RECEPTION_EVENT_TYPE_RECEPTION_CLOSED ReceptionEventType = 4
+)
+
+// Enum value maps for ReceptionEventType.
+var (
+	ReceptionEventType_name = map[int32]string{
+		0: "RECEPTION_EVENT_TYPE_UNSPECIFIED",
+		1: "RECEPTION_EVENT_TYPE_RECEPTION_OPENED",
+		2: "RECEPTION_EVENT_TYPE_PRODUCT_ADDED",
+		3: "RECEPTION_EVENT_TYPE_PRODUCT_REMOVED",
+		4: "RECEPTION_EVENT_TYPE_RECEPTION_CLOSED",
+	}
+	ReceptionEventType_value = map[string]int32{
+		"RECEPTION_EVENT_TYPE_UNSPECIFIED":      0,
+		"RECEPTION_EVENT_TYPE_RECEPTION_OPENED": 1,
+		"RECEPTION_EVENT_TYPE_PRODUCT_ADDED":    2,
+		"RECEPTION_EVENT_TYPE_PRODUCT_REMOVED":  3,
+		"RECEPTION_EVENT_TYPE_RECEPTION_CLOSED": 4,
+	}
+)
+
+func (x ReceptionEventType) Enum() *ReceptionEventType {
+	p := new(ReceptionEventType)
+	*p = x
+	return p
+}
+
+func (x ReceptionEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReceptionEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_pvz_proto_enumTypes[2].Descriptor()
+}
+
+func (ReceptionEventType) Type() protoreflect.EnumType {
+	return &file_pvz_proto_enumTypes[2]
+}
+
+func (x ReceptionEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReceptionEventType.Descriptor instead.
+func (ReceptionEventType) EnumDescriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{2}
+}
+
+// Details are optional: empty address, missing location,
+// no working hours and zero capacity mean they are unknown.
 type PVZ struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	RegistrationDate *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=registration_date,json=registrationDate,proto3" json:"registration_date,omitempty"`
 	City             string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Receptions       []*Reception           `protobuf:"bytes,4,rep,name=receptions,proto3" json:"receptions,omitempty"`
+	Address          string                 `protobuf:"bytes,5,opt,name=address,proto3" json:"address,omitempty"`
+	Location         *Location              `protobuf:"bytes,6,opt,name=location,proto3" json:"location,omitempty"`
+	WorkingHours     []*WorkingHours        `protobuf:"bytes,7,rep,name=working_hours,json=workingHours,proto3" json:"working_hours,omitempty"`
+	Capacity         int32                  `protobuf:"varint,8,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Status           PVZStatus              `protobuf:"varint,9,opt,name=status,proto3,enum=pvz.v1.PVZStatus" json:"status,omitempty"`
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -129,26 +246,71 @@ func (x *PVZ) GetCity() string {
 	return ""
 }
 
-type GetPVZListRequest struct {
+func (x *PVZ) GetReceptions() []*Reception {
+	if x != nil {
+		return x.Receptions
+	}
+	return nil
+}
+
+func (x *PVZ) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *PVZ) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *PVZ) GetWorkingHours() []*WorkingHours {
+	if x != nil {
+		return x.WorkingHours
+	}
+	return nil
+}
+
+func (x *PVZ) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *PVZ) GetStatus() PVZStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PVZStatus_PVZ_STATUS_UNSPECIFIED
+}
+
+// Geographic position in degrees.
+type Location struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPVZListRequest) Reset() {
-	*x = GetPVZListRequest{}
+func (x *Location) Reset() {
+	*x = Location{}
 	mi := &file_pvz_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPVZListRequest) String() string {
+func (x *Location) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPVZListRequest) ProtoMessage() {}
+func (*Location) ProtoMessage() {}
 
-func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
+func (x *Location) ProtoReflect() protoreflect.Message {
 	mi := &file_pvz_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -160,32 +322,50 @@ func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPVZListRequest.ProtoReflect.Descriptor instead.
-func (*GetPVZListRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Location.ProtoReflect.Descriptor instead.
+func (*Location) Descriptor() ([]byte, []int) {
 	return file_pvz_proto_rawDescGZIP(), []int{1}
 }
 
-type GetPVZListResponse struct {
+func (x *Location) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *Location) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+// Weekday is from 1 (Monday) to 7 (Sunday), opens and closes are
+// local time of PVZ formatted as HH:MM.
+type WorkingHours struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Pvzs          []*PVZ                 `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+	Weekday       int32                  `protobuf:"varint,1,opt,name=weekday,proto3" json:"weekday,omitempty"`
+	Opens         string                 `protobuf:"bytes,2,opt,name=opens,proto3" json:"opens,omitempty"`
+	Closes        string                 `protobuf:"bytes,3,opt,name=closes,proto3" json:"closes,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPVZListResponse) Reset() {
-	*x = GetPVZListResponse{}
+func (x *WorkingHours) Reset() {
+	*x = WorkingHours{}
 	mi := &file_pvz_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPVZListResponse) String() string {
+func (x *WorkingHours) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPVZListResponse) ProtoMessage() {}
+func (*WorkingHours) ProtoMessage() {}
 
-func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
+func (x *WorkingHours) ProtoReflect() protoreflect.Message {
 	mi := &file_pvz_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -197,37 +377,1619 @@ func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPVZListResponse.ProtoReflect.Descriptor instead.
-func (*GetPVZListResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use WorkingHours.ProtoReflect.Descriptor instead.
+func (*WorkingHours) Descriptor() ([]byte, []int) {
 	return file_pvz_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetPVZListResponse) GetPvzs() []*PVZ {
+func (x *WorkingHours) GetWeekday() int32 {
 	if x != nil {
-		return x.Pvzs
+		return x.Weekday
+	}
+	return 0
+}
+
+func (x *WorkingHours) GetOpens() string {
+	if x != nil {
+		return x.Opens
+	}
+	return ""
+}
+
+func (x *WorkingHours) GetCloses() string {
+	if x != nil {
+		return x.Closes
+	}
+	return ""
+}
+
+type Reception struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PvzId         string                 `protobuf:"bytes,2,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Datetime      *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Status        ReceptionStatus        `protobuf:"varint,4,opt,name=status,proto3,enum=pvz.v1.ReceptionStatus" json:"status,omitempty"`
+	Products      []*Product             `protobuf:"bytes,5,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Reception) Reset() {
+	*x = Reception{}
+	mi := &file_pvz_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Reception) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Reception) ProtoMessage() {}
+
+func (x *Reception) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Reception.ProtoReflect.Descriptor instead.
+func (*Reception) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Reception) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Reception) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *Reception) GetDatetime() *timestamp.Timestamp {
+	if x != nil {
+		return x.Datetime
 	}
 	return nil
 }
 
-var File_pvz_proto protoreflect.FileDescriptor
+func (x *Reception) GetStatus() ReceptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
 
-const file_pvz_proto_rawDesc = "" +
-	"\n" +
-	"\tpvz.proto\x12\x06pvz.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"r\n" +
-	"\x03PVZ\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\x12G\n" +
-	"\x11registration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\x12\x12\n" +
-	"\x04city\x18\x03 \x01(\tR\x04city\"\x13\n" +
-	"\x11GetPVZListRequest\"5\n" +
-	"\x12GetPVZListResponse\x12\x1f\n" +
-	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs*P\n" +
-	"\x0fReceptionStatus\x12 \n" +
-	"\x1cRECEPTION_STATUS_IN_PROGRESS\x10\x00\x12\x1b\n" +
-	"\x17RECEPTION_STATUS_CLOSED\x10\x012Q\n" +
+func (x *Reception) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// serial_number and weight in grams are empty if they were not given.
+type Product struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReceptionId   string                 `protobuf:"bytes,2,opt,name=reception_id,json=receptionId,proto3" json:"reception_id,omitempty"`
+	Datetime      *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=datetime,proto3" json:"datetime,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Weight        int32                  `protobuf:"varint,6,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Product) Reset() {
+	*x = Product{}
+	mi := &file_pvz_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Product) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Product) ProtoMessage() {}
+
+func (x *Product) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Product.ProtoReflect.Descriptor instead.
+func (*Product) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Product) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Product) GetReceptionId() string {
+	if x != nil {
+		return x.ReceptionId
+	}
+	return ""
+}
+
+func (x *Product) GetDatetime() *timestamp.Timestamp {
+	if x != nil {
+		return x.Datetime
+	}
+	return nil
+}
+
+func (x *Product) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Product) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *Product) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// Unspecified status means active.
+type CreatePVZRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	City          string                 `protobuf:"bytes,1,opt,name=city,proto3" json:"city,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Location      *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	WorkingHours  []*WorkingHours        `protobuf:"bytes,4,rep,name=working_hours,json=workingHours,proto3" json:"working_hours,omitempty"`
+	Capacity      int32                  `protobuf:"varint,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Status        PVZStatus              `protobuf:"varint,6,opt,name=status,proto3,enum=pvz.v1.PVZStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePVZRequest) Reset() {
+	*x = CreatePVZRequest{}
+	mi := &file_pvz_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePVZRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePVZRequest) ProtoMessage() {}
+
+func (x *CreatePVZRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePVZRequest.ProtoReflect.Descriptor instead.
+func (*CreatePVZRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreatePVZRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *CreatePVZRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *CreatePVZRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *CreatePVZRequest) GetWorkingHours() []*WorkingHours {
+	if x != nil {
+		return x.WorkingHours
+	}
+	return nil
+}
+
+func (x *CreatePVZRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *CreatePVZRequest) GetStatus() PVZStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PVZStatus_PVZ_STATUS_UNSPECIFIED
+}
+
+type CreatePVZResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvz           *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePVZResponse) Reset() {
+	*x = CreatePVZResponse{}
+	mi := &file_pvz_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePVZResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePVZResponse) ProtoMessage() {}
+
+func (x *CreatePVZResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePVZResponse.ProtoReflect.Descriptor instead.
+func (*CreatePVZResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreatePVZResponse) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+// Details missing from request are cleared, status is required.
+type UpdatePVZRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Location      *Location              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	WorkingHours  []*WorkingHours        `protobuf:"bytes,4,rep,name=working_hours,json=workingHours,proto3" json:"working_hours,omitempty"`
+	Capacity      int32                  `protobuf:"varint,5,opt,name=capacity,proto3" json:"capacity,omitempty"`
+	Status        PVZStatus              `protobuf:"varint,6,opt,name=status,proto3,enum=pvz.v1.PVZStatus" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePVZRequest) Reset() {
+	*x = UpdatePVZRequest{}
+	mi := &file_pvz_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePVZRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePVZRequest) ProtoMessage() {}
+
+func (x *UpdatePVZRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePVZRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePVZRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdatePVZRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *UpdatePVZRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *UpdatePVZRequest) GetLocation() *Location {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *UpdatePVZRequest) GetWorkingHours() []*WorkingHours {
+	if x != nil {
+		return x.WorkingHours
+	}
+	return nil
+}
+
+func (x *UpdatePVZRequest) GetCapacity() int32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+func (x *UpdatePVZRequest) GetStatus() PVZStatus {
+	if x != nil {
+		return x.Status
+	}
+	return PVZStatus_PVZ_STATUS_UNSPECIFIED
+}
+
+type UpdatePVZResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvz           *PVZ                   `protobuf:"bytes,1,opt,name=pvz,proto3" json:"pvz,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdatePVZResponse) Reset() {
+	*x = UpdatePVZResponse{}
+	mi := &file_pvz_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePVZResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePVZResponse) ProtoMessage() {}
+
+func (x *UpdatePVZResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePVZResponse.ProtoReflect.Descriptor instead.
+func (*UpdatePVZResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdatePVZResponse) GetPvz() *PVZ {
+	if x != nil {
+		return x.Pvz
+	}
+	return nil
+}
+
+// Pagination is cursor based: pass next_cursor of the previous page
+// to get the next one. Zero limit means the maximum page size.
+// Filters are optional. Without dates and status PVZs without
+// receptions are returned too.
+type GetPVZPaginationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartDate     *timestamp.Timestamp   `protobuf:"bytes,1,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       *timestamp.Timestamp   `protobuf:"bytes,2,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	IncludeTotal  bool                   `protobuf:"varint,6,opt,name=include_total,json=includeTotal,proto3" json:"include_total,omitempty"`
+	Status        *ReceptionStatus       `protobuf:"varint,7,opt,name=status,proto3,enum=pvz.v1.ReceptionStatus,oneof" json:"status,omitempty"`
+	City          string                 `protobuf:"bytes,8,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZPaginationRequest) Reset() {
+	*x = GetPVZPaginationRequest{}
+	mi := &file_pvz_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZPaginationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZPaginationRequest) ProtoMessage() {}
+
+func (x *GetPVZPaginationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZPaginationRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZPaginationRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPVZPaginationRequest) GetStartDate() *timestamp.Timestamp {
+	if x != nil {
+		return x.StartDate
+	}
+	return nil
+}
+
+func (x *GetPVZPaginationRequest) GetEndDate() *timestamp.Timestamp {
+	if x != nil {
+		return x.EndDate
+	}
+	return nil
+}
+
+func (x *GetPVZPaginationRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetPVZPaginationRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetPVZPaginationRequest) GetIncludeTotal() bool {
+	if x != nil {
+		return x.IncludeTotal
+	}
+	return false
+}
+
+func (x *GetPVZPaginationRequest) GetStatus() ReceptionStatus {
+	if x != nil && x.Status != nil {
+		return *x.Status
+	}
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+func (x *GetPVZPaginationRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+// next_cursor is empty on the last page, total is set only if it was requested.
+type GetPVZPaginationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvzs          []*PVZ                 `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	Total         *int32                 `protobuf:"varint,3,opt,name=total,proto3,oneof" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZPaginationResponse) Reset() {
+	*x = GetPVZPaginationResponse{}
+	mi := &file_pvz_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZPaginationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZPaginationResponse) ProtoMessage() {}
+
+func (x *GetPVZPaginationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZPaginationResponse.ProtoReflect.Descriptor instead.
+func (*GetPVZPaginationResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetPVZPaginationResponse) GetPvzs() []*PVZ {
+	if x != nil {
+		return x.Pvzs
+	}
+	return nil
+}
+
+func (x *GetPVZPaginationResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetPVZPaginationResponse) GetTotal() int32 {
+	if x != nil && x.Total != nil {
+		return *x.Total
+	}
+	return 0
+}
+
+type GetPVZListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZListRequest) Reset() {
+	*x = GetPVZListRequest{}
+	mi := &file_pvz_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZListRequest) ProtoMessage() {}
+
+func (x *GetPVZListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZListRequest.ProtoReflect.Descriptor instead.
+func (*GetPVZListRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{11}
+}
+
+type GetPVZListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pvzs          []*PVZ                 `protobuf:"bytes,1,rep,name=pvzs,proto3" json:"pvzs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPVZListResponse) Reset() {
+	*x = GetPVZListResponse{}
+	mi := &file_pvz_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPVZListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPVZListResponse) ProtoMessage() {}
+
+func (x *GetPVZListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPVZListResponse.ProtoReflect.Descriptor instead.
+func (*GetPVZListResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetPVZListResponse) GetPvzs() []*PVZ {
+	if x != nil {
+		return x.Pvzs
+	}
+	return nil
+}
+
+type CreateReceptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReceptionRequest) Reset() {
+	*x = CreateReceptionRequest{}
+	mi := &file_pvz_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReceptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReceptionRequest) ProtoMessage() {}
+
+func (x *CreateReceptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReceptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateReceptionRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateReceptionRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type CreateReceptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReceptionResponse) Reset() {
+	*x = CreateReceptionResponse{}
+	mi := &file_pvz_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReceptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReceptionResponse) ProtoMessage() {}
+
+func (x *CreateReceptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReceptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateReceptionResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateReceptionResponse) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+type CloseLastReceptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseLastReceptionRequest) Reset() {
+	*x = CloseLastReceptionRequest{}
+	mi := &file_pvz_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseLastReceptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseLastReceptionRequest) ProtoMessage() {}
+
+func (x *CloseLastReceptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseLastReceptionRequest.ProtoReflect.Descriptor instead.
+func (*CloseLastReceptionRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CloseLastReceptionRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type CloseLastReceptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reception     *Reception             `protobuf:"bytes,1,opt,name=reception,proto3" json:"reception,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseLastReceptionResponse) Reset() {
+	*x = CloseLastReceptionResponse{}
+	mi := &file_pvz_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseLastReceptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseLastReceptionResponse) ProtoMessage() {}
+
+func (x *CloseLastReceptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseLastReceptionResponse.ProtoReflect.Descriptor instead.
+func (*CloseLastReceptionResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CloseLastReceptionResponse) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+// serial_number and weight in grams may be required by the product type.
+type AddProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,3,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Weight        int32                  `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductRequest) Reset() {
+	*x = AddProductRequest{}
+	mi := &file_pvz_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductRequest) ProtoMessage() {}
+
+func (x *AddProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductRequest.ProtoReflect.Descriptor instead.
+func (*AddProductRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AddProductRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *AddProductRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type AddProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddProductResponse) Reset() {
+	*x = AddProductResponse{}
+	mi := &file_pvz_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddProductResponse) ProtoMessage() {}
+
+func (x *AddProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddProductResponse.ProtoReflect.Descriptor instead.
+func (*AddProductResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type DeleteLastProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductRequest) Reset() {
+	*x = DeleteLastProductRequest{}
+	mi := &file_pvz_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductRequest) ProtoMessage() {}
+
+func (x *DeleteLastProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *DeleteLastProductRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+type DeleteLastProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductResponse) Reset() {
+	*x = DeleteLastProductResponse{}
+	mi := &file_pvz_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductResponse) ProtoMessage() {}
+
+func (x *DeleteLastProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{20}
+}
+
+type DeleteLastProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductsRequest) Reset() {
+	*x = DeleteLastProductsRequest{}
+	mi := &file_pvz_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductsRequest) ProtoMessage() {}
+
+func (x *DeleteLastProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductsRequest.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductsRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeleteLastProductsRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *DeleteLastProductsRequest) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Products are ordered from the last added.
+type DeleteLastProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteLastProductsResponse) Reset() {
+	*x = DeleteLastProductsResponse{}
+	mi := &file_pvz_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteLastProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteLastProductsResponse) ProtoMessage() {}
+
+func (x *DeleteLastProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteLastProductsResponse.ProtoReflect.Descriptor instead.
+func (*DeleteLastProductsResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteLastProductsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+// pvz_id is required in the first message and may be omitted in the next ones.
+type StreamAddProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,3,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Weight        int32                  `protobuf:"varint,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAddProductsRequest) Reset() {
+	*x = StreamAddProductsRequest{}
+	mi := &file_pvz_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAddProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAddProductsRequest) ProtoMessage() {}
+
+func (x *StreamAddProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAddProductsRequest.ProtoReflect.Descriptor instead.
+func (*StreamAddProductsRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StreamAddProductsRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *StreamAddProductsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *StreamAddProductsRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *StreamAddProductsRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type ProductRejection struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the product in the stream.
+	Index         int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Type          string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductRejection) Reset() {
+	*x = ProductRejection{}
+	mi := &file_pvz_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductRejection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductRejection) ProtoMessage() {}
+
+func (x *ProductRejection) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductRejection.ProtoReflect.Descriptor instead.
+func (*ProductRejection) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ProductRejection) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *ProductRejection) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ProductRejection) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type StreamAddProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductIds    []string               `protobuf:"bytes,1,rep,name=product_ids,json=productIds,proto3" json:"product_ids,omitempty"`
+	Rejections    []*ProductRejection    `protobuf:"bytes,2,rep,name=rejections,proto3" json:"rejections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamAddProductsResponse) Reset() {
+	*x = StreamAddProductsResponse{}
+	mi := &file_pvz_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamAddProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamAddProductsResponse) ProtoMessage() {}
+
+func (x *StreamAddProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamAddProductsResponse.ProtoReflect.Descriptor instead.
+func (*StreamAddProductsResponse) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *StreamAddProductsResponse) GetProductIds() []string {
+	if x != nil {
+		return x.ProductIds
+	}
+	return nil
+}
+
+func (x *StreamAddProductsResponse) GetRejections() []*ProductRejection {
+	if x != nil {
+		return x.Rejections
+	}
+	return nil
+}
+
+// Both filters are optional, empty request watches every PVZ.
+// City is given by code or any name, events carry default names.
+type WatchReceptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PvzId         string                 `protobuf:"bytes,1,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	City          string                 `protobuf:"bytes,2,opt,name=city,proto3" json:"city,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchReceptionsRequest) Reset() {
+	*x = WatchReceptionsRequest{}
+	mi := &file_pvz_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchReceptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchReceptionsRequest) ProtoMessage() {}
+
+func (x *WatchReceptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchReceptionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchReceptionsRequest) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WatchReceptionsRequest) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *WatchReceptionsRequest) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+type ReceptionEvent struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Type       ReceptionEventType     `protobuf:"varint,1,opt,name=type,proto3,enum=pvz.v1.ReceptionEventType" json:"type,omitempty"`
+	PvzId      string                 `protobuf:"bytes,2,opt,name=pvz_id,json=pvzId,proto3" json:"pvz_id,omitempty"`
+	OccurredAt *timestamp.Timestamp   `protobuf:"bytes,3,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Set for reception events.
+	Reception *Reception `protobuf:"bytes,4,opt,name=reception,proto3" json:"reception,omitempty"`
+	// Set for product events.
+	Product       *Product `protobuf:"bytes,5,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceptionEvent) Reset() {
+	*x = ReceptionEvent{}
+	mi := &file_pvz_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceptionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceptionEvent) ProtoMessage() {}
+
+func (x *ReceptionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_pvz_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceptionEvent.ProtoReflect.Descriptor instead.
+func (*ReceptionEvent) Descriptor() ([]byte, []int) {
+	return file_pvz_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ReceptionEvent) GetType() ReceptionEventType {
+	if x != nil {
+		return x.Type
+	}
+	return ReceptionEventType_RECEPTION_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *ReceptionEvent) GetPvzId() string {
+	if x != nil {
+		return x.PvzId
+	}
+	return ""
+}
+
+func (x *ReceptionEvent) GetOccurredAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *ReceptionEvent) GetReception() *Reception {
+	if x != nil {
+		return x.Reception
+	}
+	return nil
+}
+
+func (x *ReceptionEvent) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+var File_pvz_proto protoreflect.FileDescriptor
+
+const file_pvz_proto_rawDesc = "" +
+	"\n" +
+	"\tpvz.proto\x12\x06pvz.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x02\n" +
+	"\x03PVZ\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12G\n" +
+	"\x11registration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x10registrationDate\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x121\n" +
+	"\n" +
+	"receptions\x18\x04 \x03(\v2\x11.pvz.v1.ReceptionR\n" +
+	"receptions\x12\x18\n" +
+	"\aaddress\x18\x05 \x01(\tR\aaddress\x12,\n" +
+	"\blocation\x18\x06 \x01(\v2\x10.pvz.v1.LocationR\blocation\x129\n" +
+	"\rworking_hours\x18\a \x03(\v2\x14.pvz.v1.WorkingHoursR\fworkingHours\x12\x1a\n" +
+	"\bcapacity\x18\b \x01(\x05R\bcapacity\x12)\n" +
+	"\x06status\x18\t \x01(\x0e2\x11.pvz.v1.PVZStatusR\x06status\"D\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"V\n" +
+	"\fWorkingHours\x12\x18\n" +
+	"\aweekday\x18\x01 \x01(\x05R\aweekday\x12\x14\n" +
+	"\x05opens\x18\x02 \x01(\tR\x05opens\x12\x16\n" +
+	"\x06closes\x18\x03 \x01(\tR\x06closes\"\xc8\x01\n" +
+	"\tReception\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x15\n" +
+	"\x06pvz_id\x18\x02 \x01(\tR\x05pvzId\x126\n" +
+	"\bdatetime\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12/\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x17.pvz.v1.ReceptionStatusR\x06status\x12+\n" +
+	"\bproducts\x18\x05 \x03(\v2\x0f.pvz.v1.ProductR\bproducts\"\xc5\x01\n" +
+	"\aProduct\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\freception_id\x18\x02 \x01(\tR\vreceptionId\x126\n" +
+	"\bdatetime\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bdatetime\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12#\n" +
+	"\rserial_number\x18\x05 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06weight\x18\x06 \x01(\x05R\x06weight\"\xf0\x01\n" +
+	"\x10CreatePVZRequest\x12\x12\n" +
+	"\x04city\x18\x01 \x01(\tR\x04city\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12,\n" +
+	"\blocation\x18\x03 \x01(\v2\x10.pvz.v1.LocationR\blocation\x129\n" +
+	"\rworking_hours\x18\x04 \x03(\v2\x14.pvz.v1.WorkingHoursR\fworkingHours\x12\x1a\n" +
+	"\bcapacity\x18\x05 \x01(\x05R\bcapacity\x12)\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x11.pvz.v1.PVZStatusR\x06status\"2\n" +
+	"\x11CreatePVZResponse\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\"\xf3\x01\n" +
+	"\x10UpdatePVZRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12,\n" +
+	"\blocation\x18\x03 \x01(\v2\x10.pvz.v1.LocationR\blocation\x129\n" +
+	"\rworking_hours\x18\x04 \x03(\v2\x14.pvz.v1.WorkingHoursR\fworkingHours\x12\x1a\n" +
+	"\bcapacity\x18\x05 \x01(\x05R\bcapacity\x12)\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x11.pvz.v1.PVZStatusR\x06status\"2\n" +
+	"\x11UpdatePVZResponse\x12\x1d\n" +
+	"\x03pvz\x18\x01 \x01(\v2\v.pvz.v1.PVZR\x03pvz\"\xbf\x02\n" +
+	"\x17GetPVZPaginationRequest\x129\n" +
+	"\n" +
+	"start_date\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartDate\x125\n" +
+	"\bend_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendDate\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\x12#\n" +
+	"\rinclude_total\x18\x06 \x01(\bR\fincludeTotal\x124\n" +
+	"\x06status\x18\a \x01(\x0e2\x17.pvz.v1.ReceptionStatusH\x00R\x06status\x88\x01\x01\x12\x12\n" +
+	"\x04city\x18\b \x01(\tR\x04cityB\t\n" +
+	"\a_statusJ\x04\b\x04\x10\x05R\x04page\"\x81\x01\n" +
+	"\x18GetPVZPaginationResponse\x12\x1f\n" +
+	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12\x19\n" +
+	"\x05total\x18\x03 \x01(\x05H\x00R\x05total\x88\x01\x01B\b\n" +
+	"\x06_total\"\x13\n" +
+	"\x11GetPVZListRequest\"5\n" +
+	"\x12GetPVZListResponse\x12\x1f\n" +
+	"\x04pvzs\x18\x01 \x03(\v2\v.pvz.v1.PVZR\x04pvzs\"/\n" +
+	"\x16CreateReceptionRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"J\n" +
+	"\x17CreateReceptionResponse\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\"2\n" +
+	"\x19CloseLastReceptionRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"M\n" +
+	"\x1aCloseLastReceptionResponse\x12/\n" +
+	"\treception\x18\x01 \x01(\v2\x11.pvz.v1.ReceptionR\treception\"{\n" +
+	"\x11AddProductRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12#\n" +
+	"\rserial_number\x18\x03 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x05R\x06weight\"?\n" +
+	"\x12AddProductResponse\x12)\n" +
+	"\aproduct\x18\x01 \x01(\v2\x0f.pvz.v1.ProductR\aproduct\"1\n" +
+	"\x18DeleteLastProductRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\"\x1b\n" +
+	"\x19DeleteLastProductResponse\"H\n" +
+	"\x19DeleteLastProductsRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\"I\n" +
+	"\x1aDeleteLastProductsResponse\x12+\n" +
+	"\bproducts\x18\x01 \x03(\v2\x0f.pvz.v1.ProductR\bproducts\"\x82\x01\n" +
+	"\x18StreamAddProductsRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12#\n" +
+	"\rserial_number\x18\x03 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x05R\x06weight\"T\n" +
+	"\x10ProductRejection\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"v\n" +
+	"\x19StreamAddProductsResponse\x12\x1f\n" +
+	"\vproduct_ids\x18\x01 \x03(\tR\n" +
+	"productIds\x128\n" +
+	"\n" +
+	"rejections\x18\x02 \x03(\v2\x18.pvz.v1.ProductRejectionR\n" +
+	"rejections\"C\n" +
+	"\x16WatchReceptionsRequest\x12\x15\n" +
+	"\x06pvz_id\x18\x01 \x01(\tR\x05pvzId\x12\x12\n" +
+	"\x04city\x18\x02 \x01(\tR\x04city\"\xf0\x01\n" +
+	"\x0eReceptionEvent\x12.\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x1a.pvz.v1.ReceptionEventTypeR\x04type\x12\x15\n" +
+	"\x06pvz_id\x18\x02 \x01(\tR\x05pvzId\x12;\n" +
+	"\voccurred_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12/\n" +
+	"\treception\x18\x04 \x01(\v2\x11.pvz.v1.ReceptionR\treception\x12)\n" +
+	"\aproduct\x18\x05 \x01(\v2\x0f.pvz.v1.ProductR\aproduct*o\n" +
+	"\tPVZStatus\x12\x1a\n" +
+	"\x16PVZ_STATUS_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11PVZ_STATUS_ACTIVE\x10\x01\x12\x18\n" +
+	"\x14PVZ_STATUS_SUSPENDED\x10\x02\x12\x15\n" +
+	"\x11PVZ_STATUS_CLOSED\x10\x03*P\n" +
+	"\x0fReceptionStatus\x12 \n" +
+	"\x1cRECEPTION_STATUS_IN_PROGRESS\x10\x00\x12\x1b\n" +
+	"\x17RECEPTION_STATUS_CLOSED\x10\x01*\xe2\x01\n" +
+	"\x12ReceptionEventType\x12$\n" +
+	" RECEPTION_EVENT_TYPE_UNSPECIFIED\x10\x00\x12)\n" +
+	"%RECEPTION_EVENT_TYPE_RECEPTION_OPENED\x10\x01\x12&\n" +
+	"\"RECEPTION_EVENT_TYPE_PRODUCT_ADDED\x10\x02\x12(\n" +
+	"$RECEPTION_EVENT_TYPE_PRODUCT_REMOVED\x10\x03\x12)\n" +
+	"%RECEPTION_EVENT_TYPE_RECEPTION_CLOSED\x10\x042\xfb\t\n" +
+	"\n" +
+	"PVZService\x12X\n" +
+	"\tCreatePVZ\x12\x18.pvz.v1.CreatePVZRequest\x1a\x19.pvz.v1.CreatePVZResponse\"\x16\x82\xd3\xe4\x93\x02\x10:\x01*\"\v/api/v2/pvz\x12j\n" +
+	"\x10GetPVZPagination\x12\x1f.pvz.v1.GetPVZPaginationRequest\x1a .pvz.v1.GetPVZPaginationResponse\"\x13\x82\xd3\xe4\x93\x02\r\x12\v/api/v2/pvz\x12]\n" +
 	"\n" +
-	"PVZService\x12C\n" +
+	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponse\"\x18\x82\xd3\xe4\x93\x02\x12\x12\x10/api/v2/pvz/list\x12a\n" +
+	"\tUpdatePVZ\x12\x18.pvz.v1.UpdatePVZRequest\x1a\x19.pvz.v1.UpdatePVZResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\x1a\x14/api/v2/pvz/{pvz_id}\x12q\n" +
+	"\x0fCreateReception\x12\x1e.pvz.v1.CreateReceptionRequest\x1a\x1f.pvz.v1.CreateReceptionResponse\"\x1d\x82\xd3\xe4\x93\x02\x17:\x01*\"\x12/api/v2/receptions\x12\x8e\x01\n" +
+	"\x12CloseLastReception\x12!.pvz.v1.CloseLastReceptionRequest\x1a\".pvz.v1.CloseLastReceptionResponse\"1\x82\xd3\xe4\x93\x02+\")/api/v2/pvz/{pvz_id}/close_last_reception\x12`\n" +
 	"\n" +
-	"GetPVZList\x12\x19.pvz.v1.GetPVZListRequest\x1a\x1a.pvz.v1.GetPVZListResponseBAZ?github.com/sudeeya/avito-assignment/internal/controller/grpc/v1b\x06proto3"
+	"AddProduct\x12\x19.pvz.v1.AddProductRequest\x1a\x1a.pvz.v1.AddProductResponse\"\x1b\x82\xd3\xe4\x93\x02\x15:\x01*\"\x10/api/v2/products\x12\x8a\x01\n" +
+	"\x11DeleteLastProduct\x12 .pvz.v1.DeleteLastProductRequest\x1a!.pvz.v1.DeleteLastProductResponse\"0\x82\xd3\xe4\x93\x02*\"(/api/v2/pvz/{pvz_id}/delete_last_product\x12\x82\x01\n" +
+	"\x12DeleteLastProducts\x12!.pvz.v1.DeleteLastProductsRequest\x1a\".pvz.v1.DeleteLastProductsResponse\"%\x82\xd3\xe4\x93\x02\x1f*\x1d/api/v2/pvz/{pvz_id}/products\x12~\n" +
+	"\x11StreamAddProducts\x12 .pvz.v1.StreamAddProductsRequest\x1a!.pvz.v1.StreamAddProductsResponse\"\"\x82\xd3\xe4\x93\x02\x1c:\x01*\"\x17/api/v2/products:stream(\x01\x12m\n" +
+	"\x0fWatchReceptions\x12\x1e.pvz.v1.WatchReceptionsRequest\x1a\x16.pvz.v1.ReceptionEvent\" \x82\xd3\xe4\x93\x02\x1a\x12\x18/api/v2/receptions:watch0\x01BAZ?github.com/sudeeya/avito-assignment/internal/controller/grpc/v1b\x06proto3"
 
 var (
 	file_pvz_proto_rawDescOnce sync.Once
@@ -241,25 +2003,101 @@ func file_pvz_proto_rawDescGZIP() []byte {
 	return file_pvz_proto_rawDescData
 }
 
-var file_pvz_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pvz_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_pvz_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_pvz_proto_goTypes = []any{
-	(ReceptionStatus)(0),        // 0: pvz.v1.ReceptionStatus
-	(*PVZ)(nil),                 // 1: pvz.v1.PVZ
-	(*GetPVZListRequest)(nil),   // 2: pvz.v1.GetPVZListRequest
-	(*GetPVZListResponse)(nil),  // 3: pvz.v1.GetPVZListResponse
-	(*timestamp.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(PVZStatus)(0),                     // 0: pvz.v1.PVZStatus
+	(ReceptionStatus)(0),               // 1: pvz.v1.ReceptionStatus
+	(ReceptionEventType)(0),            // 2: pvz.v1.ReceptionEventType
+	(*PVZ)(nil),                        // 3: pvz.v1.PVZ
+	(*Location)(nil),                   // 4: pvz.v1.Location
+	(*WorkingHours)(nil),               // 5: pvz.v1.WorkingHours
+	(*Reception)(nil),                  // 6: pvz.v1.Reception
+	(*Product)(nil),                    // 7: pvz.v1.Product
+	(*CreatePVZRequest)(nil),           // 8: pvz.v1.CreatePVZRequest
+	(*CreatePVZResponse)(nil),          // 9: pvz.v1.CreatePVZResponse
+	(*UpdatePVZRequest)(nil),           // 10: pvz.v1.UpdatePVZRequest
+	(*UpdatePVZResponse)(nil),          // 11: pvz.v1.UpdatePVZResponse
+	(*GetPVZPaginationRequest)(nil),    // 12: pvz.v1.GetPVZPaginationRequest
+	(*GetPVZPaginationResponse)(nil),   // 13: pvz.v1.GetPVZPaginationResponse
+	(*GetPVZListRequest)(nil),          // 14: pvz.v1.GetPVZListRequest
+	(*GetPVZListResponse)(nil),         // 15: pvz.v1.GetPVZListResponse
+	(*CreateReceptionRequest)(nil),     // 16: pvz.v1.CreateReceptionRequest
+	(*CreateReceptionResponse)(nil),    // 17: pvz.v1.CreateReceptionResponse
+	(*CloseLastReceptionRequest)(nil),  // 18: pvz.v1.CloseLastReceptionRequest
+	(*CloseLastReceptionResponse)(nil), // 19: pvz.v1.CloseLastReceptionResponse
+	(*AddProductRequest)(nil),          // 20: pvz.v1.AddProductRequest
+	(*AddProductResponse)(nil),         // 21: pvz.v1.AddProductResponse
+	(*DeleteLastProductRequest)(nil),   // 22: pvz.v1.DeleteLastProductRequest
+	(*DeleteLastProductResponse)(nil),  // 23: pvz.v1.DeleteLastProductResponse
+	(*DeleteLastProductsRequest)(nil),  // 24: pvz.v1.DeleteLastProductsRequest
+	(*DeleteLastProductsResponse)(nil), // 25: pvz.v1.DeleteLastProductsResponse
+	(*StreamAddProductsRequest)(nil),   // 26: pvz.v1.StreamAddProductsRequest
+	(*ProductRejection)(nil),           // 27: pvz.v1.ProductRejection
+	(*StreamAddProductsResponse)(nil),  // 28: pvz.v1.StreamAddProductsResponse
+	(*WatchReceptionsRequest)(nil),     // 29: pvz.v1.WatchReceptionsRequest
+	(*ReceptionEvent)(nil),             // 30: pvz.v1.ReceptionEvent
+	(*timestamp.Timestamp)(nil),        // 31: google.protobuf.Timestamp
 }
 var file_pvz_proto_depIdxs = []int32{
-	4, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
-	1, // 1: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
-	2, // 2: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
-	3, // 3: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
-	3, // [3:4] is the sub-list for method output_type
-	2, // [2:3] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	31, // 0: pvz.v1.PVZ.registration_date:type_name -> google.protobuf.Timestamp
+	6,  // 1: pvz.v1.PVZ.receptions:type_name -> pvz.v1.Reception
+	4,  // 2: pvz.v1.PVZ.location:type_name -> pvz.v1.Location
+	5,  // 3: pvz.v1.PVZ.working_hours:type_name -> pvz.v1.WorkingHours
+	0,  // 4: pvz.v1.PVZ.status:type_name -> pvz.v1.PVZStatus
+	31, // 5: pvz.v1.Reception.datetime:type_name -> google.protobuf.Timestamp
+	1,  // 6: pvz.v1.Reception.status:type_name -> pvz.v1.ReceptionStatus
+	7,  // 7: pvz.v1.Reception.products:type_name -> pvz.v1.Product
+	31, // 8: pvz.v1.Product.datetime:type_name -> google.protobuf.Timestamp
+	4,  // 9: pvz.v1.CreatePVZRequest.location:type_name -> pvz.v1.Location
+	5,  // 10: pvz.v1.CreatePVZRequest.working_hours:type_name -> pvz.v1.WorkingHours
+	0,  // 11: pvz.v1.CreatePVZRequest.status:type_name -> pvz.v1.PVZStatus
+	3,  // 12: pvz.v1.CreatePVZResponse.pvz:type_name -> pvz.v1.PVZ
+	4,  // 13: pvz.v1.UpdatePVZRequest.location:type_name -> pvz.v1.Location
+	5,  // 14: pvz.v1.UpdatePVZRequest.working_hours:type_name -> pvz.v1.WorkingHours
+	0,  // 15: pvz.v1.UpdatePVZRequest.status:type_name -> pvz.v1.PVZStatus
+	3,  // 16: pvz.v1.UpdatePVZResponse.pvz:type_name -> pvz.v1.PVZ
+	31, // 17: pvz.v1.GetPVZPaginationRequest.start_date:type_name -> google.protobuf.Timestamp
+	31, // 18: pvz.v1.GetPVZPaginationRequest.end_date:type_name -> google.protobuf.Timestamp
+	1,  // 19: pvz.v1.GetPVZPaginationRequest.status:type_name -> pvz.v1.ReceptionStatus
+	3,  // 20: pvz.v1.GetPVZPaginationResponse.pvzs:type_name -> pvz.v1.PVZ
+	3,  // 21: pvz.v1.GetPVZListResponse.pvzs:type_name -> pvz.v1.PVZ
+	6,  // 22: pvz.v1.CreateReceptionResponse.reception:type_name -> pvz.v1.Reception
+	6,  // 23: pvz.v1.CloseLastReceptionResponse.reception:type_name -> pvz.v1.Reception
+	7,  // 24: pvz.v1.AddProductResponse.product:type_name -> pvz.v1.Product
+	7,  // 25: pvz.v1.DeleteLastProductsResponse.products:type_name -> pvz.v1.Product
+	27, // 26: pvz.v1.StreamAddProductsResponse.rejections:type_name -> pvz.v1.ProductRejection
+	2,  // 27: pvz.v1.ReceptionEvent.type:type_name -> pvz.v1.ReceptionEventType
+	31, // 28: pvz.v1.ReceptionEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	6,  // 29: pvz.v1.ReceptionEvent.reception:type_name -> pvz.v1.Reception
+	7,  // 30: pvz.v1.ReceptionEvent.product:type_name -> pvz.v1.Product
+	8,  // 31: pvz.v1.PVZService.CreatePVZ:input_type -> pvz.v1.CreatePVZRequest
+	12, // 32: pvz.v1.PVZService.GetPVZPagination:input_type -> pvz.v1.GetPVZPaginationRequest
+	14, // 33: pvz.v1.PVZService.GetPVZList:input_type -> pvz.v1.GetPVZListRequest
+	10, // 34: pvz.v1.PVZService.UpdatePVZ:input_type -> pvz.v1.UpdatePVZRequest
+	16, // 35: pvz.v1.PVZService.CreateReception:input_type -> pvz.v1.CreateReceptionRequest
+	18, // 36: pvz.v1.PVZService.CloseLastReception:input_type -> pvz.v1.CloseLastReceptionRequest
+	20, // 37: pvz.v1.PVZService.AddProduct:input_type -> pvz.v1.AddProductRequest
+	22, // 38: pvz.v1.PVZService.DeleteLastProduct:input_type -> pvz.v1.DeleteLastProductRequest
+	24, // 39: pvz.v1.PVZService.DeleteLastProducts:input_type -> pvz.v1.DeleteLastProductsRequest
+	26, // 40: pvz.v1.PVZService.StreamAddProducts:input_type -> pvz.v1.StreamAddProductsRequest
+	29, // 41: pvz.v1.PVZService.WatchReceptions:input_type -> pvz.v1.WatchReceptionsRequest
+	9,  // 42: pvz.v1.PVZService.CreatePVZ:output_type -> pvz.v1.CreatePVZResponse
+	13, // 43: pvz.v1.PVZService.GetPVZPagination:output_type -> pvz.v1.GetPVZPaginationResponse
+	15, // 44: pvz.v1.PVZService.GetPVZList:output_type -> pvz.v1.GetPVZListResponse
+	11, // 45: pvz.v1.PVZService.UpdatePVZ:output_type -> pvz.v1.UpdatePVZResponse
+	17, // 46: pvz.v1.PVZService.CreateReception:output_type -> pvz.v1.CreateReceptionResponse
+	19, // 47: pvz.v1.PVZService.CloseLastReception:output_type -> pvz.v1.CloseLastReceptionResponse
+	21, // 48: pvz.v1.PVZService.AddProduct:output_type -> pvz.v1.AddProductResponse
+	23, // 49: pvz.v1.PVZService.DeleteLastProduct:output_type -> pvz.v1.DeleteLastProductResponse
+	25, // 50: pvz.v1.PVZService.DeleteLastProducts:output_type -> pvz.v1.DeleteLastProductsResponse
+	28, // 51: pvz.v1.PVZService.StreamAddProducts:output_type -> pvz.v1.StreamAddProductsResponse
+	30, // 52: pvz.v1.PVZService.WatchReceptions:output_type -> pvz.v1.ReceptionEvent
+	42, // [42:53] is the sub-list for method output_type
+	31, // [31:42] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_pvz_proto_init() }
@@ -267,13 +2105,15 @@ func file_pvz_proto_init() {
 	if File_pvz_proto != nil {
 		return
 	}
+	file_pvz_proto_msgTypes[9].OneofWrappers = []any{}
+	file_pvz_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pvz_proto_rawDesc), len(file_pvz_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   3,
+			NumEnums:      3,
+			NumMessages:   28,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
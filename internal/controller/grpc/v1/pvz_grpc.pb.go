@@ -20,14 +20,41 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PVZService_GetPVZList_FullMethodName = "/pvz.v1.PVZService/GetPVZList"
+	PVZService_CreatePVZ_FullMethodName          = "/pvz.v1.PVZService/CreatePVZ"
+	PVZService_GetPVZPagination_FullMethodName   = "/pvz.v1.PVZService/GetPVZPagination"
+	PVZService_GetPVZList_FullMethodName         = "/pvz.v1.PVZService/GetPVZList"
+	PVZService_UpdatePVZ_FullMethodName          = "/pvz.v1.PVZService/UpdatePVZ"
+	PVZService_CreateReception_FullMethodName    = "/pvz.v1.PVZService/CreateReception"
+	PVZService_CloseLastReception_FullMethodName = "/pvz.v1.PVZService/CloseLastReception"
+	PVZService_AddProduct_FullMethodName         = "/pvz.v1.PVZService/AddProduct"
+	PVZService_DeleteLastProduct_FullMethodName  = "/pvz.v1.PVZService/DeleteLastProduct"
+	PVZService_DeleteLastProducts_FullMethodName = "/pvz.v1.PVZService/DeleteLastProducts"
+	PVZService_StreamAddProducts_FullMethodName  = "/pvz.v1.PVZService/StreamAddProducts"
+	PVZService_WatchReceptions_FullMethodName    = "/pvz.v1.PVZService/WatchReceptions"
 )
 
 // PVZServiceClient is the client API for PVZService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HTTP annotations are served by the gateway under /api/v2.
 type PVZServiceClient interface {
+	CreatePVZ(ctx context.Context, in *CreatePVZRequest, opts ...grpc.CallOption) (*CreatePVZResponse, error)
+	GetPVZPagination(ctx context.Context, in *GetPVZPaginationRequest, opts ...grpc.CallOption) (*GetPVZPaginationResponse, error)
 	GetPVZList(ctx context.Context, in *GetPVZListRequest, opts ...grpc.CallOption) (*GetPVZListResponse, error)
+	// Replaces details and status of PVZ, its city can't be changed.
+	UpdatePVZ(ctx context.Context, in *UpdatePVZRequest, opts ...grpc.CallOption) (*UpdatePVZResponse, error)
+	CreateReception(ctx context.Context, in *CreateReceptionRequest, opts ...grpc.CallOption) (*CreateReceptionResponse, error)
+	CloseLastReception(ctx context.Context, in *CloseLastReceptionRequest, opts ...grpc.CallOption) (*CloseLastReceptionResponse, error)
+	AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*AddProductResponse, error)
+	DeleteLastProduct(ctx context.Context, in *DeleteLastProductRequest, opts ...grpc.CallOption) (*DeleteLastProductResponse, error)
+	// Undoes count last added products atomically, count defaults to 1.
+	DeleteLastProducts(ctx context.Context, in *DeleteLastProductsRequest, opts ...grpc.CallOption) (*DeleteLastProductsResponse, error)
+	// Gateway accepts newline-delimited JSON requests. Unknown PVZ or no reception
+	// in progress fail the call, products of batches added before stay added.
+	StreamAddProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamAddProductsRequest, StreamAddProductsResponse], error)
+	// Gateway responds with newline-delimited JSON events.
+	WatchReceptions(ctx context.Context, in *WatchReceptionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReceptionEvent], error)
 }
 
 type pVZServiceClient struct {
@@ -38,6 +65,26 @@ func NewPVZServiceClient(cc grpc.ClientConnInterface) PVZServiceClient {
 	return &pVZServiceClient{cc}
 }
 
+func (c *pVZServiceClient) CreatePVZ(ctx context.Context, in *CreatePVZRequest, opts ...grpc.CallOption) (*CreatePVZResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePVZResponse)
+	err := c.cc.Invoke(ctx, PVZService_CreatePVZ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) GetPVZPagination(ctx context.Context, in *GetPVZPaginationRequest, opts ...grpc.CallOption) (*GetPVZPaginationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPVZPaginationResponse)
+	err := c.cc.Invoke(ctx, PVZService_GetPVZPagination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *pVZServiceClient) GetPVZList(ctx context.Context, in *GetPVZListRequest, opts ...grpc.CallOption) (*GetPVZListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetPVZListResponse)
@@ -48,11 +95,120 @@ func (c *pVZServiceClient) GetPVZList(ctx context.Context, in *GetPVZListRequest
 	return out, nil
 }
 
+func (c *pVZServiceClient) UpdatePVZ(ctx context.Context, in *UpdatePVZRequest, opts ...grpc.CallOption) (*UpdatePVZResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdatePVZResponse)
+	err := c.cc.Invoke(ctx, PVZService_UpdatePVZ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) CreateReception(ctx context.Context, in *CreateReceptionRequest, opts ...grpc.CallOption) (*CreateReceptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateReceptionResponse)
+	err := c.cc.Invoke(ctx, PVZService_CreateReception_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) CloseLastReception(ctx context.Context, in *CloseLastReceptionRequest, opts ...grpc.CallOption) (*CloseLastReceptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseLastReceptionResponse)
+	err := c.cc.Invoke(ctx, PVZService_CloseLastReception_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) AddProduct(ctx context.Context, in *AddProductRequest, opts ...grpc.CallOption) (*AddProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddProductResponse)
+	err := c.cc.Invoke(ctx, PVZService_AddProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) DeleteLastProduct(ctx context.Context, in *DeleteLastProductRequest, opts ...grpc.CallOption) (*DeleteLastProductResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLastProductResponse)
+	err := c.cc.Invoke(ctx, PVZService_DeleteLastProduct_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) DeleteLastProducts(ctx context.Context, in *DeleteLastProductsRequest, opts ...grpc.CallOption) (*DeleteLastProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteLastProductsResponse)
+	err := c.cc.Invoke(ctx, PVZService_DeleteLastProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pVZServiceClient) StreamAddProducts(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[StreamAddProductsRequest, StreamAddProductsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PVZService_ServiceDesc.Streams[0], PVZService_StreamAddProducts_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamAddProductsRequest, StreamAddProductsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_StreamAddProductsClient = grpc.ClientStreamingClient[StreamAddProductsRequest, StreamAddProductsResponse]
+
+func (c *pVZServiceClient) WatchReceptions(ctx context.Context, in *WatchReceptionsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReceptionEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PVZService_ServiceDesc.Streams[1], PVZService_WatchReceptions_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchReceptionsRequest, ReceptionEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_WatchReceptionsClient = grpc.ServerStreamingClient[ReceptionEvent]
+
 // PVZServiceServer is the server API for PVZService service.
 // All implementations must embed UnimplementedPVZServiceServer
 // for forward compatibility.
+//
+// HTTP annotations are served by the gateway under /api/v2.
 type PVZServiceServer interface {
+	CreatePVZ(context.Context, *CreatePVZRequest) (*CreatePVZResponse, error)
+	GetPVZPagination(context.Context, *GetPVZPaginationRequest) (*GetPVZPaginationResponse, error)
 	GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error)
+	// Replaces details and status of PVZ, its city can't be changed.
+	UpdatePVZ(context.Context, *UpdatePVZRequest) (*UpdatePVZResponse, error)
+	CreateReception(context.Context, *CreateReceptionRequest) (*CreateReceptionResponse, error)
+	CloseLastReception(context.Context, *CloseLastReceptionRequest) (*CloseLastReceptionResponse, error)
+	AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error)
+	DeleteLastProduct(context.Context, *DeleteLastProductRequest) (*DeleteLastProductResponse, error)
+	// Undoes count last added products atomically, count defaults to 1.
+	DeleteLastProducts(context.Context, *DeleteLastProductsRequest) (*DeleteLastProductsResponse, error)
+	// Gateway accepts newline-delimited JSON requests. Unknown PVZ or no reception
+	// in progress fail the call, products of batches added before stay added.
+	StreamAddProducts(grpc.ClientStreamingServer[StreamAddProductsRequest, StreamAddProductsResponse]) error
+	// Gateway responds with newline-delimited JSON events.
+	WatchReceptions(*WatchReceptionsRequest, grpc.ServerStreamingServer[ReceptionEvent]) error
 	mustEmbedUnimplementedPVZServiceServer()
 }
 
@@ -63,9 +219,39 @@ type PVZServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedPVZServiceServer struct{}
 
+func (UnimplementedPVZServiceServer) CreatePVZ(context.Context, *CreatePVZRequest) (*CreatePVZResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePVZ not implemented")
+}
+func (UnimplementedPVZServiceServer) GetPVZPagination(context.Context, *GetPVZPaginationRequest) (*GetPVZPaginationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPVZPagination not implemented")
+}
 func (UnimplementedPVZServiceServer) GetPVZList(context.Context, *GetPVZListRequest) (*GetPVZListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPVZList not implemented")
 }
+func (UnimplementedPVZServiceServer) UpdatePVZ(context.Context, *UpdatePVZRequest) (*UpdatePVZResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePVZ not implemented")
+}
+func (UnimplementedPVZServiceServer) CreateReception(context.Context, *CreateReceptionRequest) (*CreateReceptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateReception not implemented")
+}
+func (UnimplementedPVZServiceServer) CloseLastReception(context.Context, *CloseLastReceptionRequest) (*CloseLastReceptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseLastReception not implemented")
+}
+func (UnimplementedPVZServiceServer) AddProduct(context.Context, *AddProductRequest) (*AddProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddProduct not implemented")
+}
+func (UnimplementedPVZServiceServer) DeleteLastProduct(context.Context, *DeleteLastProductRequest) (*DeleteLastProductResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLastProduct not implemented")
+}
+func (UnimplementedPVZServiceServer) DeleteLastProducts(context.Context, *DeleteLastProductsRequest) (*DeleteLastProductsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteLastProducts not implemented")
+}
+func (UnimplementedPVZServiceServer) StreamAddProducts(grpc.ClientStreamingServer[StreamAddProductsRequest, StreamAddProductsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamAddProducts not implemented")
+}
+func (UnimplementedPVZServiceServer) WatchReceptions(*WatchReceptionsRequest, grpc.ServerStreamingServer[ReceptionEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchReceptions not implemented")
+}
 func (UnimplementedPVZServiceServer) mustEmbedUnimplementedPVZServiceServer() {}
 func (UnimplementedPVZServiceServer) testEmbeddedByValue()                    {}
 
@@ -87,6 +273,42 @@ func RegisterPVZServiceServer(s grpc.ServiceRegistrar, srv PVZServiceServer) {
 	s.RegisterService(&PVZService_ServiceDesc, srv)
 }
 
+func _PVZService_CreatePVZ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePVZRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).CreatePVZ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_CreatePVZ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).CreatePVZ(ctx, req.(*CreatePVZRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_GetPVZPagination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPVZPaginationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).GetPVZPagination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_GetPVZPagination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).GetPVZPagination(ctx, req.(*GetPVZPaginationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PVZService_GetPVZList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetPVZListRequest)
 	if err := dec(in); err != nil {
@@ -105,6 +327,132 @@ func _PVZService_GetPVZList_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PVZService_UpdatePVZ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePVZRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).UpdatePVZ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_UpdatePVZ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).UpdatePVZ(ctx, req.(*UpdatePVZRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_CreateReception_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateReceptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).CreateReception(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_CreateReception_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).CreateReception(ctx, req.(*CreateReceptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_CloseLastReception_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseLastReceptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).CloseLastReception(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_CloseLastReception_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).CloseLastReception(ctx, req.(*CloseLastReceptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_AddProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).AddProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_AddProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).AddProduct(ctx, req.(*AddProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_DeleteLastProduct_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLastProductRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).DeleteLastProduct(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_DeleteLastProduct_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).DeleteLastProduct(ctx, req.(*DeleteLastProductRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_DeleteLastProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteLastProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PVZServiceServer).DeleteLastProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PVZService_DeleteLastProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PVZServiceServer).DeleteLastProducts(ctx, req.(*DeleteLastProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PVZService_StreamAddProducts_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PVZServiceServer).StreamAddProducts(&grpc.GenericServerStream[StreamAddProductsRequest, StreamAddProductsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_StreamAddProductsServer = grpc.ClientStreamingServer[StreamAddProductsRequest, StreamAddProductsResponse]
+
+func _PVZService_WatchReceptions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchReceptionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PVZServiceServer).WatchReceptions(m, &grpc.GenericServerStream[WatchReceptionsRequest, ReceptionEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PVZService_WatchReceptionsServer = grpc.ServerStreamingServer[ReceptionEvent]
+
 // PVZService_ServiceDesc is the grpc.ServiceDesc for PVZService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -112,11 +460,54 @@ var PVZService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "pvz.v1.PVZService",
 	HandlerType: (*PVZServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreatePVZ",
+			Handler:    _PVZService_CreatePVZ_Handler,
+		},
+		{
+			MethodName: "GetPVZPagination",
+			Handler:    _PVZService_GetPVZPagination_Handler,
+		},
 		{
 			MethodName: "GetPVZList",
 			Handler:    _PVZService_GetPVZList_Handler,
 		},
+		{
+			MethodName: "UpdatePVZ",
+			Handler:    _PVZService_UpdatePVZ_Handler,
+		},
+		{
+			MethodName: "CreateReception",
+			Handler:    _PVZService_CreateReception_Handler,
+		},
+		{
+			MethodName: "CloseLastReception",
+			Handler:    _PVZService_CloseLastReception_Handler,
+		},
+		{
+			MethodName: "AddProduct",
+			Handler:    _PVZService_AddProduct_Handler,
+		},
+		{
+			MethodName: "DeleteLastProduct",
+			Handler:    _PVZService_DeleteLastProduct_Handler,
+		},
+		{
+			MethodName: "DeleteLastProducts",
+			Handler:    _PVZService_DeleteLastProducts_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamAddProducts",
+			Handler:       _PVZService_StreamAddProducts_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchReceptions",
+			Handler:       _PVZService_WatchReceptions_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "pvz.proto",
 }
@@ -0,0 +1,73 @@
+package v1
+
+import (
+	context "context"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+func (p *pvzServiceServerImplementation) CreateReception(ctx context.Context, req *CreateReceptionRequest) (*CreateReceptionResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	reception, err := p.services.Reception.CreateReception(ctx, pvzID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &CreateReceptionResponse{
+		Reception: receptionToProto(reception),
+	}, nil
+}
+
+func (p *pvzServiceServerImplementation) CloseLastReception(ctx context.Context, req *CloseLastReceptionRequest) (*CloseLastReceptionResponse, error) {
+	pvzID, err := uuid.Parse(req.GetPvzId())
+	if err != nil {
+		return nil, invalidArgument("pvz_id", "invalid UUID")
+	}
+
+	reception, err := p.services.Reception.CloseLastReception(ctx, pvzID)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &CloseLastReceptionResponse{
+		Reception: receptionToProto(reception),
+	}, nil
+}
+
+func receptionToProto(reception model.Reception) *Reception {
+	res := &Reception{
+		Id:       reception.ID.String(),
+		PvzId:    reception.PVZID.String(),
+		Datetime: timestamppb.New(reception.Datetime),
+		Status:   receptionStatusToProto(reception.Status),
+	}
+
+	for _, product := range reception.Products {
+		res.Products = append(res.Products, productToProto(product))
+	}
+
+	return res
+}
+
+func receptionStatusToProto(status string) ReceptionStatus {
+	if status == model.ReceptionStatusClose {
+		return ReceptionStatus_RECEPTION_STATUS_CLOSED
+	}
+
+	return ReceptionStatus_RECEPTION_STATUS_IN_PROGRESS
+}
+
+func receptionStatusFromProto(status ReceptionStatus) string {
+	if status == ReceptionStatus_RECEPTION_STATUS_CLOSED {
+		return model.ReceptionStatusClose
+	}
+
+	return model.ReceptionStatusInProgress
+}
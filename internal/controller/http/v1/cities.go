@@ -0,0 +1,109 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+func newCitiesRouter(services *service.Services) *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Use(roleMiddleware(model.RoleModerator))
+
+	router.Get("/", getCitiesHandler(services.City))
+	router.Post("/", createCityHandler(services.City))
+	router.Delete("/{cityID}", deleteCityHandler(services.City))
+
+	return router
+}
+
+func getCitiesHandler(cityService service.City) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cities, err := cityService.GetCities(r.Context())
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(cities); err != nil {
+			zap.S().Errorf("encoding cities: %v", err)
+		}
+	}
+}
+
+type createCityInput struct {
+	Code  string            `json:"code"`
+	Name  string            `json:"name"`
+	Names map[string]string `json:"names"`
+}
+
+func createCityHandler(cityService service.City) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input createCityInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		city, err := cityService.CreateCity(r.Context(), model.City{
+			Code:  input.Code,
+			Name:  input.Name,
+			Names: input.Names,
+		})
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(city); err != nil {
+			zap.S().Errorf("encoding city: %v", err)
+		}
+	}
+}
+
+func deleteCityHandler(cityService service.City) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cityID, err := uuid.Parse(chi.URLParam(r, "cityID"))
+		if err != nil {
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "cityID", Reason: err.Error()})
+			return
+		}
+
+		// City is only deactivated by default.
+		var hard bool
+		if param := r.URL.Query().Get("hard"); param != "" {
+			hard, err = strconv.ParseBool(param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid hard", validationError{In: "query", Field: "hard", Reason: err.Error()})
+				return
+			}
+		}
+
+		city, err := cityService.DeleteCity(r.Context(), cityID, hard)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		if hard {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(city); err != nil {
+			zap.S().Errorf("encoding city: %v", err)
+		}
+	}
+}
@@ -2,15 +2,12 @@ package v1
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
 
-	"github.com/sudeeya/avito-assignment/internal/service"
-)
+	"github.com/google/uuid"
 
-// User roles.
-const (
-	_moderator = "moderator"
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
 )
 
 func dummyLoginHandler(authService service.Auth) http.HandlerFunc {
@@ -19,19 +16,17 @@ func dummyLoginHandler(authService service.Auth) http.HandlerFunc {
 			Role string `json:"role"`
 		}
 		if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
-			return
-		}
-
-		if user.Role != _moderator {
-			http.Error(w, "invalid role", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
 			return
 		}
 
-		token, err := authService.IssueToken(r.Context())
+		// Every dummy login gets its own subject.
+		token, err := authService.IssueToken(r.Context(), model.Identity{
+			UserID: uuid.New(),
+			Role:   user.Role,
+		})
 		if err != nil {
-			fmt.Println(err)
-			http.Error(w, "issuing token", http.StatusInternalServerError)
+			writeServiceError(w, err)
 			return
 		}
 
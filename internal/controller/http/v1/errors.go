@@ -0,0 +1,154 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+// Error codes are stable, so clients can branch on them
+// and localize messages. They match gRPC error reasons.
+const (
+	_codeInvalidRequest         = "INVALID_REQUEST"
+	_codeUnauthenticated        = "UNAUTHENTICATED"
+	_codeAccessDenied           = "ACCESS_DENIED"
+	_codeInvalidCredentials     = "INVALID_CREDENTIALS"
+	_codeInvalidEmail           = "INVALID_EMAIL"
+	_codeInvalidPassword        = "INVALID_PASSWORD"
+	_codeInvalidRefreshToken    = "INVALID_REFRESH_TOKEN"
+	_codeUnsupportedRole        = "UNSUPPORTED_ROLE"
+	_codeUserExists             = "USER_EXISTS"
+	_codePVZNotFound            = "PVZ_NOT_FOUND"
+	_codeInvalidCursor          = "INVALID_CURSOR"
+	_codeInvalidPageSize        = "INVALID_PAGE_SIZE"
+	_codeInvalidLocation        = "INVALID_LOCATION"
+	_codeInvalidWorkingHours    = "INVALID_WORKING_HOURS"
+	_codeInvalidCapacity        = "INVALID_CAPACITY"
+	_codeUnsupportedPVZStatus   = "UNSUPPORTED_PVZ_STATUS"
+	_codePVZNotActive           = "PVZ_NOT_ACTIVE"
+	_codeUnsupportedCity        = "UNSUPPORTED_CITY"
+	_codeUnsupportedProductType = "UNSUPPORTED_PRODUCT_TYPE"
+	_codeReceptionInProgress    = "RECEPTION_IN_PROGRESS"
+	_codeUnsupportedStatus      = "UNSUPPORTED_RECEPTION_STATUS"
+	_codeNoReceptionInProgress  = "NO_RECEPTION_IN_PROGRESS"
+	_codeReceptionIsEmpty       = "RECEPTION_IS_EMPTY"
+	_codeInvalidProductCount    = "INVALID_PRODUCT_COUNT"
+	_codeNotEnoughProducts      = "NOT_ENOUGH_PRODUCTS"
+	_codeSerialNumberRequired   = "SERIAL_NUMBER_REQUIRED"
+	_codeWeightRequired         = "WEIGHT_REQUIRED"
+	_codeInvalidWeight          = "INVALID_WEIGHT"
+	_codeProductTooHeavy        = "PRODUCT_TOO_HEAVY"
+	_codeInvalidProductTypeName = "INVALID_PRODUCT_TYPE_NAME"
+	_codeInvalidMaxWeight       = "INVALID_MAX_WEIGHT"
+	_codeProductTypeExists      = "PRODUCT_TYPE_EXISTS"
+	_codeProductTypeNotFound    = "PRODUCT_TYPE_NOT_FOUND"
+	_codeProductTypeInUse       = "PRODUCT_TYPE_IN_USE"
+	_codeInvalidCityName        = "INVALID_CITY_NAME"
+	_codeInvalidCode            = "INVALID_CODE"
+	_codeInvalidLanguage        = "INVALID_LANGUAGE"
+	_codeInvalidTranslation     = "INVALID_TRANSLATION"
+	_codeCityExists             = "CITY_EXISTS"
+	_codeCityNotFound           = "CITY_NOT_FOUND"
+	_codeCityHasPVZs            = "CITY_HAS_PVZS"
+	_codeInternal               = "INTERNAL"
+)
+
+type errorOutput struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Details any    `json:"details,omitempty"`
+}
+
+type errorMapping struct {
+	err    error
+	status int
+	code   string
+}
+
+// _errorMappings is the single place where service errors get
+// HTTP status: 409 for conflicts with current state, 404 for unknown
+// resources and 422 for well-formed but unsupported values.
+var _errorMappings = []errorMapping{
+	{service.ErrInvalidCredentials, http.StatusUnauthorized, _codeInvalidCredentials},
+	{service.ErrInvalidEmail, http.StatusBadRequest, _codeInvalidEmail},
+	{service.ErrInvalidPassword, http.StatusBadRequest, _codeInvalidPassword},
+	{service.ErrRoleNotAllowed, http.StatusForbidden, _codeAccessDenied},
+	{service.ErrInvalidRefreshToken, http.StatusUnauthorized, _codeInvalidRefreshToken},
+	{service.ErrUnsupportedRole, http.StatusUnprocessableEntity, _codeUnsupportedRole},
+	{service.ErrUserExists, http.StatusConflict, _codeUserExists},
+	{service.ErrPVZNotFound, http.StatusNotFound, _codePVZNotFound},
+	{service.ErrInvalidCursor, http.StatusBadRequest, _codeInvalidCursor},
+	{service.ErrInvalidPageSize, http.StatusUnprocessableEntity, _codeInvalidPageSize},
+	{service.ErrInvalidLocation, http.StatusUnprocessableEntity, _codeInvalidLocation},
+	{service.ErrInvalidWorkingHours, http.StatusUnprocessableEntity, _codeInvalidWorkingHours},
+	{service.ErrInvalidCapacity, http.StatusUnprocessableEntity, _codeInvalidCapacity},
+	{service.ErrUnsupportedPVZStatus, http.StatusUnprocessableEntity, _codeUnsupportedPVZStatus},
+	{service.ErrPVZNotActive, http.StatusConflict, _codePVZNotActive},
+	{service.ErrUnsupportedCity, http.StatusUnprocessableEntity, _codeUnsupportedCity},
+	{service.ErrUnsupportedProductType, http.StatusUnprocessableEntity, _codeUnsupportedProductType},
+	{service.ErrReceptionInProgress, http.StatusConflict, _codeReceptionInProgress},
+	{service.ErrUnsupportedReceptionStatus, http.StatusUnprocessableEntity, _codeUnsupportedStatus},
+	{service.ErrNoReceptionInProgress, http.StatusConflict, _codeNoReceptionInProgress},
+	{service.ErrReceptionIsEmpty, http.StatusConflict, _codeReceptionIsEmpty},
+	{service.ErrInvalidProductCount, http.StatusUnprocessableEntity, _codeInvalidProductCount},
+	{service.ErrNotEnoughProducts, http.StatusConflict, _codeNotEnoughProducts},
+	{service.ErrSerialNumberRequired, http.StatusUnprocessableEntity, _codeSerialNumberRequired},
+	{service.ErrWeightRequired, http.StatusUnprocessableEntity, _codeWeightRequired},
+	{service.ErrInvalidWeight, http.StatusUnprocessableEntity, _codeInvalidWeight},
+	{service.ErrProductTooHeavy, http.StatusUnprocessableEntity, _codeProductTooHeavy},
+	{service.ErrInvalidProductTypeName, http.StatusUnprocessableEntity, _codeInvalidProductTypeName},
+	{service.ErrInvalidMaxWeight, http.StatusUnprocessableEntity, _codeInvalidMaxWeight},
+	{service.ErrProductTypeExists, http.StatusConflict, _codeProductTypeExists},
+	{service.ErrProductTypeNotFound, http.StatusNotFound, _codeProductTypeNotFound},
+	{service.ErrProductTypeInUse, http.StatusConflict, _codeProductTypeInUse},
+	{service.ErrInvalidCityName, http.StatusUnprocessableEntity, _codeInvalidCityName},
+	{service.ErrInvalidCode, http.StatusUnprocessableEntity, _codeInvalidCode},
+	{service.ErrInvalidLanguage, http.StatusUnprocessableEntity, _codeInvalidLanguage},
+	{service.ErrInvalidTranslation, http.StatusUnprocessableEntity, _codeInvalidTranslation},
+	{service.ErrCityExists, http.StatusConflict, _codeCityExists},
+	{service.ErrCityNotFound, http.StatusNotFound, _codeCityNotFound},
+	{service.ErrCityHasPVZs, http.StatusConflict, _codeCityHasPVZs},
+}
+
+// writeServiceError writes service error with its status and code.
+// Unknown errors are reported as internal without leaking their text.
+func writeServiceError(w http.ResponseWriter, err error) {
+	for _, m := range _errorMappings {
+		if errors.Is(err, m.err) {
+			writeError(w, m.status, m.code, m.err.Error(), nil)
+			return
+		}
+	}
+
+	zap.S().Errorf("Handling HTTP request: %v", err)
+
+	writeError(w, http.StatusInternalServerError, _codeInternal, "internal error", nil)
+}
+
+// writeInvalidRequest reports request that cannot be decoded or is invalid.
+func writeInvalidRequest(w http.ResponseWriter, message string, details ...validationError) {
+	var d any
+	if len(details) > 0 {
+		d = details
+	}
+
+	writeError(w, http.StatusBadRequest, _codeInvalidRequest, message, d)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string, details any) {
+	output := errorOutput{
+		Code:    code,
+		Message: message,
+		Details: details,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(output); err != nil {
+		zap.S().Errorf("encoding error: %v", err)
+	}
+}
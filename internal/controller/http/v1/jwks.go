@@ -0,0 +1,28 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+type jwksOutput struct {
+	Keys []model.JWK `json:"keys"`
+}
+
+func jwksHandler(authService service.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		output := jwksOutput{
+			Keys: authService.PublicKeys(r.Context()),
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(output); err != nil {
+			zap.S().Errorf("encoding jwks: %v", err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+type loginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func loginHandler(authService service.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input loginInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		tokens, err := authService.Login(r.Context(), input.Email, input.Password)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(tokens); err != nil {
+			zap.S().Errorf("encoding tokens: %v", err)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+type logoutInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func logoutHandler(authService service.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Request body is optional, access token is revoked anyway.
+		var input logoutInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil && !errors.Is(err, io.EOF) {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		identity, ok := service.IdentityFromContext(r.Context())
+		if !ok {
+			writeError(w, http.StatusForbidden, _codeAccessDenied, "access denied", nil)
+			return
+		}
+
+		err := authService.Revoke(r.Context(), identity, input.RefreshToken)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -8,8 +9,9 @@ import (
 )
 
 const (
-	_authorizationHeader = "Authorization"
-	_bearer              = "Bearer "
+	_authorizationHeader  = "Authorization"
+	_acceptLanguageHeader = "Accept-Language"
+	_bearer               = "Bearer "
 )
 
 func authMiddleware(authService service.Auth) func(http.Handler) http.Handler {
@@ -18,15 +20,36 @@ func authMiddleware(authService service.Auth) func(http.Handler) http.Handler {
 			bearerString := r.Header.Get(_authorizationHeader)
 
 			if bearerString == "" {
-				http.Error(w, "empty bearer string", http.StatusForbidden)
+				writeError(w, http.StatusForbidden, _codeUnauthenticated, "empty bearer string", nil)
 				return
 			}
 
 			tokenString := strings.TrimPrefix(bearerString, _bearer)
 
-			err := authService.VerifyToken(r.Context(), tokenString)
-			if err != nil {
-				http.Error(w, "wrong token", http.StatusForbidden)
+			identity, err := authService.VerifyToken(r.Context(), tokenString)
+			if errors.Is(err, service.ErrCannotVerifyToken) { // Token may be valid, so it is not reported as wrong.
+				writeServiceError(w, err)
+				return
+			} else if err != nil {
+				writeError(w, http.StatusForbidden, _codeUnauthenticated, "wrong token", nil)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(service.WithIdentity(r.Context(), identity)))
+		}
+
+		return http.HandlerFunc(h)
+	}
+}
+
+// roleMiddleware allows only identities with one of the roles.
+// It must be used after authMiddleware.
+func roleMiddleware(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		h := func(w http.ResponseWriter, r *http.Request) {
+			identity, ok := service.IdentityFromContext(r.Context())
+			if !ok || !identity.HasRole(roles...) {
+				writeError(w, http.StatusForbidden, _codeAccessDenied, "access denied", nil)
 				return
 			}
 
@@ -36,3 +59,17 @@ func authMiddleware(authService service.Auth) func(http.Handler) http.Handler {
 		return http.HandlerFunc(h)
 	}
 }
+
+// languageMiddleware passes languages from Accept-Language to services,
+// which return names of cities and product types in them.
+func languageMiddleware(next http.Handler) http.Handler {
+	h := func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get(_acceptLanguageHeader); header != "" {
+			r = r.WithContext(service.WithLanguages(r.Context(), service.ParseAcceptLanguage(header)))
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(h)
+}
@@ -0,0 +1,177 @@
+package v1
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"go.uber.org/zap"
+)
+
+//go:embed openapi.json
+var _openAPISpec []byte
+
+const _swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>PVZ service API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+func init() {
+	// kin-openapi does not check uuid format by default.
+	openapi3.DefineStringFormatValidator("uuid", openapi3.NewRegexpFormatValidator(openapi3.FormatOfStringForUUIDOfRFC4122))
+}
+
+// loadOpenAPISpec parses and validates the embedded specification.
+func loadOpenAPISpec() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(_openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("loading openapi spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("validating openapi spec: %w", err)
+	}
+
+	return doc, nil
+}
+
+func openAPISpecHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(_openAPISpec)
+	}
+}
+
+func swaggerUIHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(_swaggerUIPage))
+	}
+}
+
+type validationError struct {
+	In     string `json:"in"`
+	Field  string `json:"field,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// validationMiddleware rejects requests that do not match the spec.
+// Routes missing from the spec are passed through untouched.
+// Authentication is left to authMiddleware.
+func validationMiddleware(doc *openapi3.T) (func(http.Handler) http.Handler, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("creating openapi router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(next http.Handler) http.Handler {
+		h := func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if errors.Is(err, routers.ErrPathNotFound) || errors.Is(err, routers.ErrMethodNotAllowed) {
+				next.ServeHTTP(w, r)
+				return
+			} else if err != nil {
+				zap.S().Errorf("finding openapi route: %v", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				writeInvalidRequest(w, "request does not match specification", collectValidationErrors(err)...)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(h)
+	}, nil
+}
+
+// collectValidationErrors flattens errors returned by openapi3filter
+// into a list with one entry per invalid field.
+func collectValidationErrors(err error) []validationError {
+	// Type switch instead of errors.As: RequestError unwraps
+	// to a MultiError of schema errors and would lose its location.
+	var requestErr *openapi3filter.RequestError
+	switch e := err.(type) {
+	case openapi3.MultiError:
+		var result []validationError
+		for _, inner := range e {
+			result = append(result, collectValidationErrors(inner)...)
+		}
+		return result
+	case *openapi3filter.RequestError:
+		requestErr = e
+	default:
+		return []validationError{{In: "request", Reason: err.Error()}}
+	}
+
+	in, field := "body", ""
+	if requestErr.Parameter != nil {
+		in, field = requestErr.Parameter.In, requestErr.Parameter.Name
+	}
+
+	var schemaErrs openapi3.MultiError
+	switch e := requestErr.Err.(type) {
+	case nil:
+		return []validationError{{In: in, Field: field, Reason: requestErr.Reason}}
+	case openapi3.MultiError:
+		schemaErrs = e
+	default:
+		schemaErrs = openapi3.MultiError{e}
+	}
+
+	result := make([]validationError, 0, len(schemaErrs))
+	for _, e := range schemaErrs {
+		result = append(result, schemaValidationError(in, field, e))
+	}
+
+	return result
+}
+
+func schemaValidationError(in, field string, err error) validationError {
+	schemaErr, ok := err.(*openapi3.SchemaError)
+	if !ok {
+		return validationError{In: in, Field: field, Reason: err.Error()}
+	}
+
+	if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+		field = strings.Join(pointer, ".")
+	}
+
+	return validationError{In: in, Field: field, Reason: schemaErr.Reason}
+}
@@ -0,0 +1,128 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+func newProductTypesRouter(services *service.Services) *chi.Mux {
+	router := chi.NewRouter()
+
+	router.With(roleMiddleware(model.RoleEmployee, model.RoleModerator)).
+		Get("/", getProductTypesHandler(services.ProductType))
+	router.With(roleMiddleware(model.RoleModerator)).
+		Post("/", createProductTypeHandler(services.ProductType))
+	router.With(roleMiddleware(model.RoleModerator)).
+		Put("/{productTypeID}", updateProductTypeHandler(services.ProductType))
+	router.With(roleMiddleware(model.RoleModerator)).
+		Delete("/{productTypeID}", deleteProductTypeHandler(services.ProductType))
+
+	return router
+}
+
+type productTypeInput struct {
+	Code                 string            `json:"code"`
+	Name                 string            `json:"name"`
+	Names                map[string]string `json:"names"`
+	SerialNumberRequired bool              `json:"serial_number_required"`
+	MaxWeight            int               `json:"max_weight"`
+}
+
+func (i productTypeInput) toModel(id uuid.UUID) model.ProductType {
+	return model.ProductType{
+		ID:                   id,
+		Code:                 i.Code,
+		Name:                 i.Name,
+		Names:                i.Names,
+		SerialNumberRequired: i.SerialNumberRequired,
+		MaxWeight:            i.MaxWeight,
+	}
+}
+
+func getProductTypesHandler(productTypeService service.ProductType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		productTypes, err := productTypeService.GetProductTypes(r.Context())
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(productTypes); err != nil {
+			zap.S().Errorf("encoding product types: %v", err)
+		}
+	}
+}
+
+func createProductTypeHandler(productTypeService service.ProductType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input productTypeInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		productType, err := productTypeService.CreateProductType(r.Context(), input.toModel(uuid.Nil))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(productType); err != nil {
+			zap.S().Errorf("encoding product type: %v", err)
+		}
+	}
+}
+
+func updateProductTypeHandler(productTypeService service.ProductType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		productTypeID, err := uuid.Parse(chi.URLParam(r, "productTypeID"))
+		if err != nil {
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "productTypeID", Reason: err.Error()})
+			return
+		}
+
+		var input productTypeInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		productType, err := productTypeService.UpdateProductType(r.Context(), input.toModel(productTypeID))
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(productType); err != nil {
+			zap.S().Errorf("encoding product type: %v", err)
+		}
+	}
+}
+
+func deleteProductTypeHandler(productTypeService service.ProductType) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		productTypeID, err := uuid.Parse(chi.URLParam(r, "productTypeID"))
+		if err != nil {
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "productTypeID", Reason: err.Error()})
+			return
+		}
+
+		if err := productTypeService.DeleteProductType(r.Context(), productTypeID); err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
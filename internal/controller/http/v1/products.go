@@ -2,43 +2,47 @@ package v1
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/service"
 )
 
 func newProductsRouter(services *service.Services) *chi.Mux {
 	router := chi.NewRouter()
 
-	router.Post("/", addProductHandler(services.Product))
+	router.With(roleMiddleware(model.RoleEmployee)).
+		Post("/", addProductHandler(services.Product))
 
 	return router
 }
 
 type addProductInput struct {
-	Type  string    `json:"type"`
-	PVZID uuid.UUID `json:"pvz_id"`
+	Type         string    `json:"type"`
+	PVZID        uuid.UUID `json:"pvz_id"`
+	SerialNumber string    `json:"serial_number"`
+	Weight       int       `json:"weight"`
 }
 
 func addProductHandler(productService service.Product) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var input addProductInput
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
 			return
 		}
 
-		product, err := productService.AddProduct(r.Context(), input.PVZID, input.Type)
-		if errors.Is(err, service.ErrUnsupportedProductType) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		} else if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		product, err := productService.AddProduct(r.Context(), input.PVZID, model.NewProduct{
+			Type:         input.Type,
+			SerialNumber: input.SerialNumber,
+			Weight:       input.Weight,
+		})
+		if err != nil {
+			writeServiceError(w, err)
 			return
 		}
 
@@ -2,8 +2,6 @@ package v1
 
 import (
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,21 +10,53 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/service"
 )
 
 func newPVZRouter(services *service.Services) *chi.Mux {
 	router := chi.NewRouter()
 
-	router.Get("/", getPVZPaginationHandler(services.PVZ))
-	router.Post("/", createPVZHandler(services.PVZ))
-	router.Post("/{pvzID}/close_last_reception", closeLastReceptionHandler(services.Reception))
-	router.Post("/{pvzID}/delete_last_product", deleteLastProductHandler(services.Product))
+	router.With(roleMiddleware(model.RoleEmployee, model.RoleModerator)).
+		Get("/", getPVZPaginationHandler(services.PVZ))
+	router.With(roleMiddleware(model.RoleModerator)).
+		Post("/", createPVZHandler(services.PVZ))
+	router.With(roleMiddleware(model.RoleModerator)).
+		Put("/{pvzID}", updatePVZHandler(services.PVZ))
+	router.With(roleMiddleware(model.RoleEmployee)).
+		Post("/{pvzID}/close_last_reception", closeLastReceptionHandler(services.Reception))
+	router.With(roleMiddleware(model.RoleEmployee)).
+		Post("/{pvzID}/delete_last_product", deleteLastProductHandler(services.Product))
+	router.With(roleMiddleware(model.RoleEmployee)).
+		Delete("/{pvzID}/products", deleteLastProductsHandler(services.Product))
 
 	return router
 }
 
+// pvzDetailsInput holds fields of PVZ that can be changed after it is created.
+type pvzDetailsInput struct {
+	Address      string               `json:"address"`
+	Location     *model.Location      `json:"location"`
+	WorkingHours []model.WorkingHours `json:"working_hours"`
+	Capacity     int                  `json:"capacity"`
+	Status       string               `json:"status"`
+}
+
+func (i pvzDetailsInput) toModel(id uuid.UUID, city string) model.PVZ {
+	return model.PVZ{
+		ID:           id,
+		City:         city,
+		Address:      i.Address,
+		Location:     i.Location,
+		WorkingHours: i.WorkingHours,
+		Capacity:     i.Capacity,
+		Status:       i.Status,
+	}
+}
+
 type createPVZInput struct {
+	pvzDetailsInput
+
 	ID               uuid.UUID `json:"id"`
 	RegistrationDate time.Time `json:"registration_date"`
 	City             string    `json:"city"`
@@ -36,16 +66,14 @@ func createPVZHandler(pvzService service.PVZ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var input createPVZInput
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
 			return
 		}
 
-		pvz, err := pvzService.CreatePVZ(r.Context(), input.City)
-		if errors.Is(err, service.ErrUnsupportedCity) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		} else if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		// ID is generated by database.
+		pvz, err := pvzService.CreatePVZ(r.Context(), input.toModel(uuid.Nil, input.City))
+		if err != nil {
+			writeServiceError(w, err)
 			return
 		}
 
@@ -57,44 +85,87 @@ func createPVZHandler(pvzService service.PVZ) http.HandlerFunc {
 	}
 }
 
-func getPVZPaginationHandler(pvzService service.PVZ) http.HandlerFunc {
+func updatePVZHandler(pvzService service.PVZ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		params := r.URL.Query()
-
-		fmt.Println(params.Get("startDate"))
-
-		start, err := time.Parse(time.DateOnly, params.Get("startDate"))
+		pvzID, err := uuid.Parse(chi.URLParam(r, "pvzID"))
 		if err != nil {
-			http.Error(w, "invalid startDate", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "pvzID", Reason: err.Error()})
 			return
 		}
 
-		end, err := time.Parse(time.DateOnly, params.Get("endDate"))
-		if err != nil {
-			http.Error(w, "invalid endDate", http.StatusBadRequest)
+		var input pvzDetailsInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
 			return
 		}
 
-		limit, err := strconv.Atoi(params.Get("limit"))
+		pvz, err := pvzService.UpdatePVZ(r.Context(), input.toModel(pvzID, ""))
 		if err != nil {
-			http.Error(w, "invalid limit", http.StatusBadRequest)
+			writeServiceError(w, err)
 			return
 		}
 
-		offset, err := strconv.Atoi(params.Get("page"))
-		if err != nil {
-			http.Error(w, "invalid page", http.StatusBadRequest)
-			return
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(pvz); err != nil {
+			zap.S().Errorf("encoding pvz: %v", err)
+		}
+	}
+}
+
+func getPVZPaginationHandler(pvzService service.PVZ) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+
+		// Filters are optional, zero values don't filter.
+		filter := model.PVZFilter{
+			Status: params.Get("status"),
+			City:   params.Get("city"),
 		}
 
-		pvzs, err := pvzService.GetPVZPagination(r.Context(), start, end, limit, offset)
+		var err error
+		if param := params.Get("startDate"); param != "" {
+			filter.StartDate, err = time.Parse(time.DateOnly, param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid startDate", validationError{In: "query", Field: "startDate", Reason: err.Error()})
+				return
+			}
+		}
+
+		if param := params.Get("endDate"); param != "" {
+			filter.EndDate, err = time.Parse(time.DateOnly, param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid endDate", validationError{In: "query", Field: "endDate", Reason: err.Error()})
+				return
+			}
+		}
+
+		// Zero limit means the maximum page size.
+		var limit int
+		if param := params.Get("limit"); param != "" {
+			limit, err = strconv.Atoi(param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid limit", validationError{In: "query", Field: "limit", Reason: err.Error()})
+				return
+			}
+		}
+
+		var withTotal bool
+		if param := params.Get("total"); param != "" {
+			withTotal, err = strconv.ParseBool(param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid total", validationError{In: "query", Field: "total", Reason: err.Error()})
+				return
+			}
+		}
+
+		page, err := pvzService.GetPVZPagination(r.Context(), filter, params.Get("cursor"), limit, withTotal)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, err)
 			return
 		}
 
 		w.Header().Set("Content-Type", "application/json")
-		if err := json.NewEncoder(w).Encode(pvzs); err != nil {
+		if err := json.NewEncoder(w).Encode(page); err != nil {
 			zap.S().Errorf("encoding pvzs: %v", err)
 		}
 	}
@@ -104,13 +175,13 @@ func closeLastReceptionHandler(receptionService service.Reception) http.HandlerF
 	return func(w http.ResponseWriter, r *http.Request) {
 		pvzID, err := uuid.Parse(chi.URLParam(r, "pvzID"))
 		if err != nil {
-			http.Error(w, "invalid UUID", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "pvzID", Reason: err.Error()})
 			return
 		}
 
 		reception, err := receptionService.CloseLastReception(r.Context(), pvzID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, err)
 			return
 		}
 
@@ -125,19 +196,47 @@ func deleteLastProductHandler(productService service.Product) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		pvzID, err := uuid.Parse(chi.URLParam(r, "pvzID"))
 		if err != nil {
-			http.Error(w, "invalid UUID", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "pvzID", Reason: err.Error()})
 			return
 		}
 
 		err = productService.DeleteLastProduct(r.Context(), pvzID)
-		if errors.Is(err, service.ErrReceptionIsEmpty) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		} else if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err != nil {
+			writeServiceError(w, err)
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
+
+func deleteLastProductsHandler(productService service.Product) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		pvzID, err := uuid.Parse(chi.URLParam(r, "pvzID"))
+		if err != nil {
+			writeInvalidRequest(w, "invalid UUID", validationError{In: "path", Field: "pvzID", Reason: err.Error()})
+			return
+		}
+
+		// Only the last product is deleted by default.
+		count := 1
+		if param := r.URL.Query().Get("count"); param != "" {
+			count, err = strconv.Atoi(param)
+			if err != nil {
+				writeInvalidRequest(w, "invalid count", validationError{In: "query", Field: "count", Reason: err.Error()})
+				return
+			}
+		}
+
+		products, err := productService.DeleteLastProducts(r.Context(), pvzID, count)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(products); err != nil {
+			zap.S().Errorf("encoding products: %v", err)
+		}
+	}
+}
@@ -8,13 +8,15 @@ import (
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/service"
 )
 
 func newReceptionsRouter(services *service.Services) *chi.Mux {
 	router := chi.NewRouter()
 
-	router.Post("/", createReceptionHandler(services.Reception))
+	router.With(roleMiddleware(model.RoleEmployee)).
+		Post("/", createReceptionHandler(services.Reception))
 
 	return router
 }
@@ -27,13 +29,13 @@ func createReceptionHandler(receptionService service.Reception) http.HandlerFunc
 	return func(w http.ResponseWriter, r *http.Request) {
 		var input createReceptionInput
 		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-			http.Error(w, "invalid request body", http.StatusBadRequest)
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
 			return
 		}
 
 		reception, err := receptionService.CreateReception(r.Context(), input.PVZID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeServiceError(w, err)
 			return
 		}
 
@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/service"
+)
+
+type registerInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+}
+
+func registerHandler(authService service.Auth) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var input registerInput
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			writeInvalidRequest(w, "invalid request body", validationError{In: "body", Reason: err.Error()})
+			return
+		}
+
+		if input.Email == "" || input.Password == "" {
+			writeInvalidRequest(w, "email and password are required")
+			return
+		}
+
+		user, err := authService.Register(r.Context(), input.Email, input.Password, input.Role)
+		if err != nil {
+			writeServiceError(w, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(user); err != nil {
+			zap.S().Errorf("encoding user: %v", err)
+		}
+	}
+}
@@ -1,34 +1,65 @@
 package v1
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/service"
 )
 
-func NewRouter(services *service.Services) *chi.Mux {
+// NewRouter serves hand-written handlers under /api/v1
+// and handlers generated from the proto by gateway under /api/v2.
+// Requests to /api/v1 are validated against the OpenAPI spec.
+func NewRouter(services *service.Services, gateway http.Handler) (*chi.Mux, error) {
+	doc, err := loadOpenAPISpec()
+	if err != nil {
+		return nil, err
+	}
+
+	validator, err := validationMiddleware(doc)
+	if err != nil {
+		return nil, fmt.Errorf("creating validation middleware: %w", err)
+	}
+
 	router := chi.NewRouter()
 
 	router.Use(middleware.Logger)
 	router.Use(middleware.Recoverer)
+	router.Use(validator)
+
+	router.Get("/.well-known/jwks.json", jwksHandler(services.Auth))
 
 	router.Route("/api/v1", func(r chi.Router) {
+		r.Use(languageMiddleware)
+
 		r.Get("/", func(w http.ResponseWriter, _ *http.Request) {
 			w.Write([]byte("API v1 is running"))
 		})
+		r.Get("/openapi.json", openAPISpecHandler())
+		r.Get("/docs", swaggerUIHandler())
 		r.Post("/dummyLogin", dummyLoginHandler(services.Auth))
+		r.Post("/register", registerHandler(services.Auth))
+		r.Post("/login", loginHandler(services.Auth))
+		r.Post("/refresh", refreshHandler(services.Auth))
 
 		r.Group(func(r chi.Router) {
 			r.Use(authMiddleware(services.Auth))
 
+			r.Post("/logout", logoutHandler(services.Auth))
+			r.With(roleMiddleware(model.RoleModerator)).Post("/users", registerHandler(services.Auth))
 			r.Mount("/pvz", newPVZRouter(services))
 			r.Mount("/receptions", newReceptionsRouter(services))
 			r.Mount("/products", newProductsRouter(services))
+			r.Mount("/product_types", newProductTypesRouter(services))
+			r.Mount("/cities", newCitiesRouter(services))
 		})
 	})
 
-	return router
+	router.Handle("/api/v2/*", gateway)
+
+	return router, nil
 }
@@ -0,0 +1,61 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+// Number of events a subscriber may lag behind before events are dropped for it.
+const _subscriberBufferSize = 256
+
+// Bus delivers events to all subscribers within the process.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan model.Event]struct{}
+}
+
+func New() *Bus {
+	return &Bus{
+		subscribers: make(map[chan model.Event]struct{}),
+	}
+}
+
+// Publish sends event to every subscriber without blocking.
+// Slow subscribers miss events instead of slowing down publishers.
+func (b *Bus) Publish(event model.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			zap.S().Warnf("Dropping %s event for slow subscriber", event.Type)
+		}
+	}
+}
+
+// Subscribe returns channel of published events.
+// The channel is closed when ctx is done.
+func (b *Bus) Subscribe(ctx context.Context) <-chan model.Event {
+	ch := make(chan model.Event, _subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		close(ch)
+		b.mu.Unlock()
+	}()
+
+	return ch
+}
@@ -0,0 +1,43 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/sudeeya/avito-assignment/internal/config"
+	v1 "github.com/sudeeya/avito-assignment/internal/controller/grpc/v1"
+)
+
+// NewHandler returns REST handler generated from the proto annotations.
+// It proxies requests to the local gRPC server, so they pass through the same interceptors.
+func NewHandler(ctx context.Context, cfg config.ServerConfig) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+	)
+
+	endpoint := "localhost:" + strconv.Itoa(cfg.ServerGRPCPort)
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+
+	if err := v1.RegisterPVZServiceHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
+		return nil, fmt.Errorf("registering pvz service handler: %w", err)
+	}
+
+	return mux, nil
+}
@@ -2,14 +2,27 @@ package grpcserver
 
 import (
 	"google.golang.org/grpc"
+	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	"github.com/sudeeya/avito-assignment/internal/config"
 	v1 "github.com/sudeeya/avito-assignment/internal/controller/grpc/v1"
 )
 
-func NewServer(serviceServer v1.PVZServiceServer) *grpc.Server {
-	server := grpc.NewServer()
+func NewServer(
+	cfg config.ServerConfig,
+	serviceServer v1.PVZServiceServer,
+	healthServer healthgrpc.HealthServer,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	server := grpc.NewServer(opts...)
 
 	v1.RegisterPVZServiceServer(server, serviceServer)
+	healthgrpc.RegisterHealthServer(server, healthServer)
+
+	if cfg.ServerGRPCReflection {
+		reflection.Register(server)
+	}
 
 	return server
 }
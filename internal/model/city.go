@@ -0,0 +1,23 @@
+package model
+
+import "github.com/google/uuid"
+
+// City is a city where PVZs can be created while it is active.
+// Name is in DefaultLanguage, Names are its translations by language.
+type City struct {
+	ID       uuid.UUID         `json:"id"`
+	Code     string            `json:"code"`
+	Name     string            `json:"name"`
+	Names    map[string]string `json:"names,omitempty"`
+	IsActive bool              `json:"is_active"`
+}
+
+// LocalizedName returns name in the first of languages having translation.
+func (c City) LocalizedName(languages ...string) string {
+	return localize(c.Name, c.Names, languages)
+}
+
+// LookupKeys returns code and names by which city can be found.
+func (c City) LookupKeys() []string {
+	return lookupKeys(c.Code, c.Name, c.Names)
+}
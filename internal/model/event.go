@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types.
+const (
+	EventReceptionOpened = "reception_opened"
+	EventProductAdded    = "product_added"
+	EventProductRemoved  = "product_removed"
+	EventReceptionClosed = "reception_closed"
+)
+
+// Event describes a change of PVZ receptions.
+// Reception is set for reception events, Product is set for product events.
+type Event struct {
+	Type       string    `json:"type"`
+	PVZID      uuid.UUID `json:"pvz_id"`
+	Reception  Reception `json:"reception,omitzero"`
+	Product    Product   `json:"product,omitzero"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Identity describes the owner of a verified token.
+type Identity struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Role      string    `json:"role"`
+	TokenID   uuid.UUID `json:"-"`
+	ExpiresAt time.Time `json:"-"`
+}
+
+// HasRole reports whether identity has one of the roles.
+func (i Identity) HasRole(roles ...string) bool {
+	return slices.Contains(roles, i.Role)
+}
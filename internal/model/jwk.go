@@ -0,0 +1,17 @@
+package model
+
+// JWK is a public JSON Web Key as described in RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+
+	// RSA keys.
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519 keys.
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "strings"
+
+// DefaultLanguage is language of names of cities and product types
+// which are returned if there is no translation.
+const DefaultLanguage = "ru"
+
+// LookupKey normalizes code or name of city or product type,
+// so lookups don't depend on case and surrounding spaces.
+func LookupKey(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// localize returns translation to the first language having one.
+func localize(name string, names map[string]string, languages []string) string {
+	for _, language := range languages {
+		if language == DefaultLanguage {
+			return name
+		}
+
+		if translation, ok := names[language]; ok {
+			return translation
+		}
+	}
+
+	return name
+}
+
+// lookupKeys returns keys by which city or product type can be found.
+func lookupKeys(code, name string, names map[string]string) []string {
+	keys := make([]string, 0, len(names)+2)
+	keys = append(keys, LookupKey(code), LookupKey(name))
+	for _, translation := range names {
+		keys = append(keys, LookupKey(translation))
+	}
+
+	return keys
+}
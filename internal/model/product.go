@@ -11,4 +11,22 @@ type Product struct {
 	ReceptionID uuid.UUID `json:"reception_id,omitempty,omitzero"`
 	Datetime    time.Time `json:"datetime"`
 	Type        string    `json:"type"`
+	// SerialNumber and Weight in grams are empty if they were not given.
+	SerialNumber string `json:"serial_number,omitempty"`
+	Weight       int    `json:"weight,omitempty"`
+}
+
+// NewProduct is a product to be added to in progress reception.
+type NewProduct struct {
+	Type         string
+	SerialNumber string
+	Weight       int
+}
+
+// ProductRejection describes a product which was not added.
+// Index is the position of the product in the request.
+type ProductRejection struct {
+	Index  int    `json:"index"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
 }
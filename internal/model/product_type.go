@@ -0,0 +1,26 @@
+package model
+
+import "github.com/google/uuid"
+
+// ProductType is a type of products which PVZ accepts.
+// Its attributes are checked when product is added.
+// Name is in DefaultLanguage, Names are its translations by language.
+type ProductType struct {
+	ID                   uuid.UUID         `json:"id"`
+	Code                 string            `json:"code"`
+	Name                 string            `json:"name"`
+	Names                map[string]string `json:"names,omitempty"`
+	SerialNumberRequired bool              `json:"serial_number_required"`
+	// MaxWeight is in grams, zero means no limit.
+	MaxWeight int `json:"max_weight,omitempty"`
+}
+
+// LocalizedName returns name in the first of languages having translation.
+func (t ProductType) LocalizedName(languages ...string) string {
+	return localize(t.Name, t.Names, languages)
+}
+
+// LookupKeys returns code and names by which product type can be found.
+func (t ProductType) LookupKeys() []string {
+	return lookupKeys(t.Code, t.Name, t.Names)
+}
@@ -6,9 +6,61 @@ import (
 	"github.com/google/uuid"
 )
 
+// PVZ statuses. Only active PVZ accepts new receptions.
+const (
+	PVZStatusActive    = "active"
+	PVZStatusSuspended = "suspended"
+	PVZStatusClosed    = "closed"
+)
+
+// PVZ details are optional: empty address, nil location, no working hours
+// and zero capacity mean they are unknown.
 type PVZ struct {
-	ID               uuid.UUID   `json:"id"`
-	RegistrationDate time.Time   `json:"registration_date"`
-	City             string      `json:"city"`
-	Receptions       []Reception `json:"receptions,omitempty,omitzero"`
+	ID               uuid.UUID      `json:"id"`
+	RegistrationDate time.Time      `json:"registration_date"`
+	City             string         `json:"city"`
+	Address          string         `json:"address,omitempty"`
+	Location         *Location      `json:"location,omitempty"`
+	WorkingHours     []WorkingHours `json:"working_hours,omitempty"`
+	Capacity         int            `json:"capacity,omitempty"`
+	Status           string         `json:"status"`
+	Receptions       []Reception    `json:"receptions,omitempty,omitzero"`
+}
+
+// Location is geographic position of PVZ in degrees.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// WorkingHours is the time PVZ is open on weekday, 1 is Monday and 7 is Sunday.
+// Opens and Closes are local time of PVZ formatted as "15:04".
+type WorkingHours struct {
+	Weekday int    `json:"weekday"`
+	Opens   string `json:"opens"`
+	Closes  string `json:"closes"`
+}
+
+// PVZFilter selects PVZs by city and by their receptions. Zero fields
+// don't filter. PVZs without receptions match only if no reception
+// field is set.
+type PVZFilter struct {
+	StartDate time.Time
+	EndDate   time.Time
+	Status    string
+	City      string
+}
+
+// PVZCursor points to the last PVZ of a page in (registration_date, id) order.
+type PVZCursor struct {
+	RegistrationDate time.Time `json:"registration_date"`
+	ID               uuid.UUID `json:"id"`
+}
+
+// PVZPage is a page of PVZs. NextCursor is empty on the last page,
+// Total is set only if it was requested.
+type PVZPage struct {
+	Items      []PVZ  `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
 }
@@ -6,6 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// Reception statuses.
+const (
+	ReceptionStatusInProgress = "in_progress"
+	ReceptionStatusClose      = "close"
+)
+
 type Reception struct {
 	ID       uuid.UUID `json:"id"`
 	PVZID    uuid.UUID `json:"pvz_id,omitempty,omitzero"`
@@ -0,0 +1,16 @@
+package model
+
+import "github.com/google/uuid"
+
+// User roles.
+const (
+	RoleEmployee  = "employee"
+	RoleModerator = "moderator"
+)
+
+type User struct {
+	ID           uuid.UUID `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+}
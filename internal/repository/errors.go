@@ -3,9 +3,21 @@ package repository
 import "errors"
 
 var (
+	ErrPVZNotFound            = errors.New("pvz was not found")
+	ErrPVZNotActive           = errors.New("pvz is not active")
 	ErrUnsupportedCity        = errors.New("city is not supported")
+	ErrCityExists             = errors.New("city already exists")
+	ErrCityNotFound           = errors.New("city was not found")
+	ErrCityHasPVZs            = errors.New("city has pvzs")
 	ErrUnsupportedProductType = errors.New("product type is not supported")
+	ErrProductTypeExists      = errors.New("product type already exists")
+	ErrProductTypeNotFound    = errors.New("product type was not found")
+	ErrProductTypeInUse       = errors.New("product type has products")
 	ErrReceptionInProgress    = errors.New("last reception is in progress")
 	ErrNoReceptionInProgress  = errors.New("no reception is in progress")
 	ErrReceptionIsEmpty       = errors.New("reception is empty")
+	ErrNotEnoughProducts      = errors.New("reception has fewer products than requested")
+	ErrUserExists             = errors.New("user already exists")
+	ErrUserNotFound           = errors.New("user was not found")
+	ErrRefreshTokenNotFound   = errors.New("refresh token was not found")
 )
@@ -0,0 +1,281 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// GetCities implements repository.Repository.
+func (p *postgres) GetCities(_ context.Context) ([]model.City, error) {
+	return p.refs.allCities(), nil
+}
+
+// GetCity implements repository.Repository.
+func (p *postgres) GetCity(ctx context.Context, key string) (model.City, error) {
+	city, ok, err := p.cityByKey(ctx, key)
+	if err != nil {
+		return model.City{}, err
+	} else if !ok { // City was not found.
+		return model.City{}, repository.ErrCityNotFound
+	}
+
+	return city, nil
+}
+
+// cityByKey looks city up in cache. City missing from cache could be
+// created by another replica which notification is not received yet,
+// so it is looked up in database by the key alone.
+func (p *postgres) cityByKey(ctx context.Context, key string) (model.City, bool, error) {
+	if city, ok := p.refs.city(key); ok {
+		return city, true, nil
+	}
+
+	query, args, err := p.builder.
+		Select(
+			"r.id",
+			"r.code",
+			"r.name",
+			"r.is_active",
+		).
+		Column(translationsColumn(_citiesTable)).
+		From("cities AS r").
+		Where(lookupKeyCondition(_citiesTable, key)).
+		ToSql()
+	if err != nil {
+		return model.City{}, false, fmt.Errorf("building query: %w", err)
+	}
+
+	var city model.City
+	err = p.pool.QueryRow(ctx, query, args...).Scan(
+		&city.ID,
+		&city.Code,
+		&city.Name,
+		&city.IsActive,
+		&city.Names,
+	)
+	if errors.Is(err, pgx.ErrNoRows) { // City was not found.
+		return model.City{}, false, nil
+	} else if err != nil { // Some error.
+		return model.City{}, false, fmt.Errorf("selecting city: %w", err)
+	}
+
+	return city, true, nil
+}
+
+// selectCities selects all cities with their translations from database.
+func (p *postgres) selectCities(ctx context.Context) ([]model.City, error) {
+	query, args, err := p.builder.
+		Select(
+			"id",
+			"code",
+			"name",
+			"is_active",
+		).
+		From("cities").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting cities: %w", err)
+	}
+	defer rows.Close()
+
+	cities := make([]model.City, 0)
+	for rows.Next() {
+		var city model.City
+
+		err := rows.Scan(
+			&city.ID,
+			&city.Code,
+			&city.Name,
+			&city.IsActive,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+
+		cities = append(cities, city)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
+	}
+
+	translations, err := p.selectTranslations(ctx, _citiesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cities {
+		cities[i].Names = translations[cities[i].ID]
+	}
+
+	return cities, nil
+}
+
+// CreateCity implements repository.Repository.
+func (p *postgres) CreateCity(ctx context.Context, city model.City) (model.City, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return model.City{}, fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.lockReferenceTable(ctx, tx, _citiesTable); err != nil {
+		return model.City{}, err
+	}
+
+	// Deactivated city with the same name is activated again. Its code is
+	// stable for integrations, so the city can't be activated with other code.
+	query, args, err := p.builder.
+		Select(
+			"id",
+			"code",
+			"is_active",
+		).
+		From("cities").
+		Where("LOWER(name) = ?", model.LookupKey(city.Name)).
+		ToSql()
+	if err != nil {
+		return model.City{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var (
+		cityID   uuid.UUID
+		code     string
+		isActive bool
+	)
+	err = tx.QueryRow(ctx, query, args...).Scan(&cityID, &code, &isActive)
+	if err == nil && (isActive || code != city.Code) { // Active city or city with other code exists.
+		return model.City{}, repository.ErrCityExists
+	} else if err != nil && !errors.Is(err, pgx.ErrNoRows) { // Some error.
+		return model.City{}, fmt.Errorf("selecting city: %w", err)
+	}
+
+	taken, err := p.lookupKeysTaken(ctx, tx, _citiesTable, cityID, city.LookupKeys())
+	if err != nil {
+		return model.City{}, err
+	} else if taken { // Another city has the same code or name.
+		return model.City{}, repository.ErrCityExists
+	}
+
+	if cityID == uuid.Nil {
+		query, args, err = p.builder.
+			Insert("cities").
+			Columns("code", "name").
+			Values(city.Code, city.Name).
+			Suffix("RETURNING id").
+			ToSql()
+	} else {
+		query, args, err = p.builder.
+			Update("cities").
+			Set("is_active", true).
+			Where("id = ?", cityID).
+			Suffix("RETURNING id").
+			ToSql()
+	}
+	if err != nil {
+		return model.City{}, fmt.Errorf("building query: %w", err)
+	}
+
+	err = tx.QueryRow(ctx, query, args...).Scan(&cityID)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolationCode { // City with the code or name exists.
+		return model.City{}, repository.ErrCityExists
+	} else if err != nil { // Some error.
+		return model.City{}, fmt.Errorf("inserting city: %w", err)
+	}
+
+	err = p.replaceTranslations(ctx, tx, _citiesTable, cityID, city.Names)
+	if err != nil {
+		return model.City{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return model.City{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return p.reloadedCity(ctx, cityID)
+}
+
+// DeactivateCity implements repository.Repository.
+func (p *postgres) DeactivateCity(ctx context.Context, cityID uuid.UUID) (model.City, error) {
+	query, args, err := p.builder.
+		Update("cities").
+		Set("is_active", false).
+		Where("id = ?", cityID).
+		ToSql()
+	if err != nil {
+		return model.City{}, fmt.Errorf("building query: %w", err)
+	}
+
+	tag, err := p.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return model.City{}, fmt.Errorf("updating city: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 { // City was not found.
+		return model.City{}, repository.ErrCityNotFound
+	}
+
+	return p.reloadedCity(ctx, cityID)
+}
+
+// DeleteCity implements repository.Repository.
+func (p *postgres) DeleteCity(ctx context.Context, cityID uuid.UUID) error {
+	query, args, err := p.builder.
+		Delete("cities").
+		Where("id = ?", cityID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	tag, err := p.pool.Exec(ctx, query, args...)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolationCode { // City is referenced by PVZs.
+		return repository.ErrCityHasPVZs
+	} else if err != nil { // Some error.
+		return fmt.Errorf("deleting city: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 { // City was not found.
+		return repository.ErrCityNotFound
+	}
+
+	if err := p.loadReferences(ctx, "cities"); err != nil {
+		return fmt.Errorf("loading cities: %w", err)
+	}
+
+	return nil
+}
+
+// reloadedCity reloads cache after city was changed, so this replica
+// sees the change right away, and returns the city from it.
+func (p *postgres) reloadedCity(ctx context.Context, cityID uuid.UUID) (model.City, error) {
+	if err := p.loadReferences(ctx, "cities"); err != nil {
+		return model.City{}, fmt.Errorf("loading cities: %w", err)
+	}
+
+	city, ok := p.refs.cityByID(cityID)
+	if !ok { // City was deleted concurrently.
+		return model.City{}, repository.ErrCityNotFound
+	}
+
+	return city, nil
+}
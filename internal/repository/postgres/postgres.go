@@ -1,14 +1,17 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
-	"time"
+	"slices"
+	"strings"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
@@ -25,10 +28,10 @@ const (
 	_closeStatus      = "close"
 )
 
-// Pagination defaults.
+// PostgreSQL error codes.
 const (
-	_defaultLimit  = 10
-	_defaultOffset = 0
+	_foreignKeyViolationCode = "23503"
+	_uniqueViolationCode     = "23505"
 )
 
 var _ repository.Repository = (*postgres)(nil)
@@ -36,8 +39,11 @@ var _ repository.Repository = (*postgres)(nil)
 type postgres struct {
 	pool    *pgxpool.Pool
 	builder squirrel.StatementBuilderType
+	refs    *referenceCache
 }
 
+// NewPostgres connects to database, migrates it and loads reference data cache.
+// Cache is kept up to date until ctx is done.
 func NewPostgres(ctx context.Context, cfg config.DBConfig) (*postgres, error) {
 	zap.L().Info("Establishing a connection to the database...")
 	pool, err := pgxpool.New(ctx, cfg.PostgresDSN)
@@ -57,98 +63,211 @@ func NewPostgres(ctx context.Context, cfg config.DBConfig) (*postgres, error) {
 
 	builder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
-	return &postgres{
+	p := &postgres{
 		pool:    pool,
 		builder: builder,
-	}, nil
+		refs:    newReferenceCache(),
+	}
+
+	zap.L().Info("Loading reference data...")
+	if err := p.loadReferences(ctx, ""); err != nil {
+		return nil, fmt.Errorf("loading reference data: %w", err)
+	}
+
+	go p.listenReferences(ctx)
+
+	return p, nil
+}
+
+// Ping implements repository.Repository.
+func (p *postgres) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
 }
 
 // CreatePVZ implements repository.Repository.
-func (p *postgres) CreatePVZ(ctx context.Context, city string) (model.PVZ, error) {
-	tx, err := p.pool.Begin(ctx)
+func (p *postgres) CreatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error) {
+	c, ok, err := p.cityByKey(ctx, pvz.City)
 	if err != nil {
-		return model.PVZ{}, fmt.Errorf("initiating transaction: %w", err)
+		return model.PVZ{}, err
+	} else if !ok || !c.IsActive { // City was not found or is not active.
+		return model.PVZ{}, repository.ErrUnsupportedCity
 	}
-	defer tx.Rollback(ctx)
 
-	// Check if the city is supported.
+	// City could be deactivated by another replica before cache was refreshed,
+	// so insert checks it again. Types of values selected along with city
+	// can't be inferred, so they are cast to types of columns.
+	latitude, longitude := locationColumns(pvz.Location)
 	query, args, err := p.builder.
-		Select("id").
-		From("cities").
-		Where("name = ?", city).
+		Insert("pvzs").
+		Columns(
+			"city_id",
+			"address",
+			"latitude",
+			"longitude",
+			"working_hours",
+			"capacity",
+			"status",
+		).
+		Select(squirrel.
+			Select("id").
+			Column("?::TEXT", pvz.Address).
+			Column("?::DOUBLE PRECISION", latitude).
+			Column("?::DOUBLE PRECISION", longitude).
+			Column("?::JSONB", workingHoursColumn(pvz.WorkingHours)).
+			Column("?::INTEGER", nullIfZero(pvz.Capacity)).
+			Column("?::pvz_status", pvz.Status).
+			From("cities").
+			Where("id = ? AND is_active", c.ID),
+		).
+		Suffix("RETURNING id, registration_date").
 		ToSql()
 	if err != nil {
 		return model.PVZ{}, fmt.Errorf("building query: %w", err)
 	}
 
-	var cityID uuid.UUID
-	err = tx.QueryRow(ctx, query, args...).Scan(&cityID)
-	if errors.Is(err, pgx.ErrNoRows) { // City was not found.
+	pvz.City = c.Name
+	err = p.pool.QueryRow(ctx, query, args...).Scan(&pvz.ID, &pvz.RegistrationDate)
+	if errors.Is(err, pgx.ErrNoRows) { // City was deactivated.
 		return model.PVZ{}, repository.ErrUnsupportedCity
 	} else if err != nil { // Some error.
-		return model.PVZ{}, fmt.Errorf("selecting city: %w", err)
+		return model.PVZ{}, fmt.Errorf("inserting pvz: %w", err)
 	}
 
-	// City was found, so create pvz.
-	query, args, err = p.builder.
-		Insert("pvzs").
-		Columns("city_id").
-		Values(cityID).
-		Suffix("RETURNING id, registration_date").
+	return pvz, nil
+}
+
+// UpdatePVZ implements repository.Repository.
+func (p *postgres) UpdatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error) {
+	latitude, longitude := locationColumns(pvz.Location)
+	query, args, err := p.builder.
+		Update("pvzs AS p").
+		Set("address", pvz.Address).
+		Set("latitude", latitude).
+		Set("longitude", longitude).
+		Set("working_hours", workingHoursColumn(pvz.WorkingHours)).
+		Set("capacity", nullIfZero(pvz.Capacity)).
+		Set("status", pvz.Status).
+		From("cities AS c").
+		Where("p.city_id = c.id AND p.id = ?", pvz.ID).
+		Suffix("RETURNING " + strings.Join(_pvzColumns, ", ")).
 		ToSql()
 	if err != nil {
 		return model.PVZ{}, fmt.Errorf("building query: %w", err)
 	}
 
-	pvz := model.PVZ{
-		City: city,
+	updated, err := scanPVZ(p.pool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) { // PVZ was not found.
+		return model.PVZ{}, repository.ErrPVZNotFound
+	} else if err != nil { // Some error.
+		return model.PVZ{}, fmt.Errorf("updating pvz: %w", err)
 	}
-	err = tx.QueryRow(ctx, query, args...).Scan(&pvz.ID, &pvz.RegistrationDate)
+
+	return updated, nil
+}
+
+// GetPVZPagination implements repository.Repository.
+func (p *postgres) GetPVZPagination(ctx context.Context, filter model.PVZFilter, after *model.PVZCursor, limit int) ([]model.PVZ, error) {
+	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		return model.PVZ{}, fmt.Errorf("inserting pvz: %w", err)
+		return nil, fmt.Errorf("initiating transaction: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	err = tx.Commit(ctx)
+	// Select page of pvzs matching filter.
+	builder := p.builder.
+		Select(_pvzColumns...).
+		From("pvzs AS p").
+		LeftJoin("cities AS c ON p.city_id = c.id").
+		Where(pvzFilterCondition(filter)).
+		OrderBy("p.registration_date", "p.id").
+		Limit(uint64(limit))
+	if after != nil { // Keyset pagination: continue right after the cursor.
+		builder = builder.Where("(p.registration_date, p.id) > (?, ?)", after.RegistrationDate, after.ID)
+	}
+
+	query, args, err := builder.ToSql()
 	if err != nil {
-		return model.PVZ{}, fmt.Errorf("committing transaction: %w", err)
+		return nil, fmt.Errorf("building query: %w", err)
 	}
 
-	return pvz, nil
-}
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting pvzs: %w", err)
+	}
+	defer rows.Close()
 
-// GetPVZsByRegistrationDate implements repository.Repository.
-func (p *postgres) GetPVZPagination(ctx context.Context, start, end time.Time, limit, offset int) ([]model.PVZ, error) {
-	if limit <= 0 || limit > _defaultLimit {
-		limit = _defaultLimit
+	pvzs := make([]model.PVZ, 0)
+	for rows.Next() {
+		pvz, err := scanPVZ(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+
+		pvzs = append(pvzs, pvz)
 	}
 
-	if offset < 0 {
-		offset = _defaultOffset
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
 	}
 
-	tx, err := p.pool.Begin(ctx)
+	// Select matching receptions of all pvzs in one query.
+	pvzIDs := make([]uuid.UUID, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		pvzIDs = append(pvzIDs, pvz.ID)
+	}
+
+	receptions, err := p.selectReceptions(ctx, tx, pvzIDs, filter)
 	if err != nil {
-		return nil, fmt.Errorf("initiating transaction: %w", err)
+		return nil, err
+	}
+
+	// Select products of all receptions in one query.
+	var receptionIDs []uuid.UUID
+	for _, pvzReceptions := range receptions {
+		for _, reception := range pvzReceptions {
+			receptionIDs = append(receptionIDs, reception.ID)
+		}
+	}
+
+	products, err := p.selectProducts(ctx, tx, receptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pvzs {
+		pvzs[i].Receptions = receptions[pvzs[i].ID]
+		for j := range pvzs[i].Receptions {
+			pvzs[i].Receptions[j].Products = products[pvzs[i].Receptions[j].ID]
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return pvzs, nil
+}
+
+// selectReceptions returns receptions of pvzs matching filter
+// grouped by PVZ ID and ordered by datetime.
+func (p *postgres) selectReceptions(ctx context.Context, tx pgx.Tx, pvzIDs []uuid.UUID, filter model.PVZFilter) (map[uuid.UUID][]model.Reception, error) {
+	receptions := make(map[uuid.UUID][]model.Reception, len(pvzIDs))
+	if len(pvzIDs) == 0 {
+		return receptions, nil
 	}
-	defer tx.Rollback(ctx)
 
-	// Select pvzs with in progress receptions.
 	query, args, err := p.builder.
 		Select(
-			"p.id",
-			"p.registration_date",
-			"c.name",
-			"r.id AS reception_id",
+			"r.id",
+			"r.pvz_id",
 			"r.datetime",
 			"r.status",
 		).
-		Options("DISTINCT ON (p.id)").
-		From("pvzs AS p").
-		LeftJoin("cities AS c ON p.city_id = c.id").
-		LeftJoin("receptions AS r ON r.pvz_id = p.id").
-		Where("r.status = ? AND r.datetime BETWEEN ? AND ?", _inProgressStatus, start, end).
-		Limit(uint64(limit)).
-		Offset(uint64(offset)).
+		From("receptions AS r").
+		Where("r.pvz_id = ANY(?)", pvzIDs).
+		Where(receptionFilterCondition(filter)).
+		OrderBy("r.datetime", "r.id").
 		ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("building query: %w", err)
@@ -156,22 +275,16 @@ func (p *postgres) GetPVZPagination(ctx context.Context, start, end time.Time, l
 
 	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("selecting pvzs and in progress receptions: %w", err)
+		return nil, fmt.Errorf("selecting receptions: %w", err)
 	}
 	defer rows.Close()
 
-	pvzs := make([]model.PVZ, 0)
 	for rows.Next() {
-		var (
-			pvz       model.PVZ
-			reception model.Reception
-		)
+		var reception model.Reception
 
 		err := rows.Scan(
-			&pvz.ID,
-			&pvz.RegistrationDate,
-			&pvz.City,
 			&reception.ID,
+			&reception.PVZID,
 			&reception.Datetime,
 			&reception.Status,
 		)
@@ -179,73 +292,140 @@ func (p *postgres) GetPVZPagination(ctx context.Context, start, end time.Time, l
 			return nil, fmt.Errorf("scanning row: %w", err)
 		}
 
-		pvz.Receptions = append(pvz.Receptions, reception)
-
-		pvzs = append(pvzs, pvz)
+		receptions[reception.PVZID] = append(receptions[reception.PVZID], reception)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterating rows: %w", err)
 	}
 
-	// For each reception select its products.
-	for _, pvz := range pvzs {
-		query, args, err = p.builder.
-			Select(
-				"p.id",
-				"p.datetime",
-				"t.name",
-			).
-			From("products AS p").
-			LeftJoin("product_types AS t ON p.product_type_id = t.id").
-			Where("p.reception_id = ?", pvz.Receptions[0].ID).
-			ToSql()
-		if err != nil {
-			return nil, fmt.Errorf("building query: %w", err)
-		}
+	return receptions, nil
+}
+
+// selectProducts returns products of receptions grouped by reception ID
+// in order they were added.
+func (p *postgres) selectProducts(ctx context.Context, tx pgx.Tx, receptionIDs []uuid.UUID) (map[uuid.UUID][]model.Product, error) {
+	products := make(map[uuid.UUID][]model.Product, len(receptionIDs))
+	if len(receptionIDs) == 0 {
+		return products, nil
+	}
+
+	query, args, err := p.builder.
+		Select(
+			"p.id",
+			"p.reception_id",
+			"p.datetime",
+			"t.name",
+			"COALESCE(p.serial_number, '')",
+			"COALESCE(p.weight, 0)",
+		).
+		From("products AS p").
+		LeftJoin("product_types AS t ON p.product_type_id = t.id").
+		Where("p.reception_id = ANY(?)", receptionIDs).
+		OrderBy("p.seq").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting products: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			product     model.Product
+			receptionID uuid.UUID
+		)
 
-		rows, err := tx.Query(ctx, query, args...)
+		err := rows.Scan(
+			&product.ID,
+			&receptionID,
+			&product.Datetime,
+			&product.Type,
+			&product.SerialNumber,
+			&product.Weight,
+		)
 		if err != nil {
-			return nil, fmt.Errorf("selecting products: %w", err)
+			return nil, fmt.Errorf("scanning row: %w", err)
 		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var product model.Product
-
-			err := rows.Scan(
-				&product.ID,
-				&product.Datetime,
-				&product.Type,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("scanning row: %w", err)
-			}
 
-			pvz.Receptions[0].Products = append(pvz.Receptions[0].Products, product)
-		}
+		products[receptionID] = append(products[receptionID], product)
+	}
 
-		if err := rows.Err(); err != nil {
-			return nil, fmt.Errorf("iterating rows: %w", err)
-		}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
 	}
 
-	err = tx.Commit(ctx)
+	return products, nil
+}
+
+// CountPVZ implements repository.Repository.
+func (p *postgres) CountPVZ(ctx context.Context, filter model.PVZFilter) (int, error) {
+	query, args, err := p.builder.
+		Select("COUNT(*)").
+		From("pvzs AS p").
+		LeftJoin("cities AS c ON p.city_id = c.id").
+		Where(pvzFilterCondition(filter)).
+		ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("committing transaction: %w", err)
+		return 0, fmt.Errorf("building query: %w", err)
 	}
 
-	return pvzs, nil
+	var count int
+	if err := p.pool.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting pvzs: %w", err)
+	}
+
+	return count, nil
+}
+
+// pvzFilterCondition selects pvzs matching filter, it expects pvzs aliased as p
+// and cities as c. Without reception filters pvzs without receptions match too.
+func pvzFilterCondition(filter model.PVZFilter) squirrel.Sqlizer {
+	condition := squirrel.And{}
+
+	if filter.City != "" {
+		condition = append(condition, squirrel.Eq{"c.name": filter.City})
+	}
+
+	if receptions := receptionFilterCondition(filter); len(receptions) > 0 {
+		subQuery := squirrel.
+			Select("1").
+			From("receptions AS r").
+			Where("r.pvz_id = p.id").
+			Where(receptions)
+		condition = append(condition, squirrel.Expr("EXISTS (?)", subQuery))
+	}
+
+	return condition
+}
+
+// receptionFilterCondition selects receptions matching filter, it expects receptions aliased as r.
+func receptionFilterCondition(filter model.PVZFilter) squirrel.And {
+	condition := squirrel.And{}
+
+	if !filter.StartDate.IsZero() {
+		condition = append(condition, squirrel.GtOrEq{"r.datetime": filter.StartDate})
+	}
+
+	if !filter.EndDate.IsZero() {
+		condition = append(condition, squirrel.LtOrEq{"r.datetime": filter.EndDate})
+	}
+
+	if filter.Status != "" {
+		condition = append(condition, squirrel.Eq{"r.status": filter.Status})
+	}
+
+	return condition
 }
 
 // GetPVZList implements repository.Repository.
 func (p *postgres) GetPVZList(ctx context.Context) ([]model.PVZ, error) {
 	query, args, err := p.builder.
-		Select(
-			"p.id",
-			"p.registration_date",
-			"c.name",
-		).
+		Select(_pvzColumns...).
 		From("pvzs AS p").
 		LeftJoin("cities AS c ON p.city_id = c.id").
 		ToSql()
@@ -261,13 +441,7 @@ func (p *postgres) GetPVZList(ctx context.Context) ([]model.PVZ, error) {
 
 	pvzs := make([]model.PVZ, 0)
 	for rows.Next() {
-		var pvz model.PVZ
-
-		err := rows.Scan(
-			&pvz.ID,
-			&pvz.RegistrationDate,
-			&pvz.City,
-		)
+		pvz, err := scanPVZ(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scanning row: %w", err)
 		}
@@ -282,6 +456,28 @@ func (p *postgres) GetPVZList(ctx context.Context) ([]model.PVZ, error) {
 	return pvzs, nil
 }
 
+// GetPVZ implements repository.Repository.
+func (p *postgres) GetPVZ(ctx context.Context, pvzID uuid.UUID) (model.PVZ, error) {
+	query, args, err := p.builder.
+		Select(_pvzColumns...).
+		From("pvzs AS p").
+		LeftJoin("cities AS c ON p.city_id = c.id").
+		Where("p.id = ?", pvzID).
+		ToSql()
+	if err != nil {
+		return model.PVZ{}, fmt.Errorf("building query: %w", err)
+	}
+
+	pvz, err := scanPVZ(p.pool.QueryRow(ctx, query, args...))
+	if errors.Is(err, pgx.ErrNoRows) { // PVZ was not found.
+		return model.PVZ{}, repository.ErrPVZNotFound
+	} else if err != nil { // Some error.
+		return model.PVZ{}, fmt.Errorf("selecting pvz: %w", err)
+	}
+
+	return pvz, nil
+}
+
 // CreateReception implements repository.Repository.
 func (p *postgres) CreateReception(ctx context.Context, pvzID uuid.UUID) (model.Reception, error) {
 	tx, err := p.pool.Begin(ctx)
@@ -290,6 +486,16 @@ func (p *postgres) CreateReception(ctx context.Context, pvzID uuid.UUID) (model.
 	}
 	defer tx.Rollback(ctx)
 
+	status, err := p.lockPVZ(ctx, tx, pvzID)
+	if err != nil {
+		return model.Reception{}, err
+	}
+
+	// Status can't be changed concurrently while PVZ row is locked.
+	if status != model.PVZStatusActive {
+		return model.Reception{}, repository.ErrPVZNotActive
+	}
+
 	// Check if there is a reception with "in_progress" status.
 	query, args, err := p.builder.
 		Select("receptions.id").
@@ -324,7 +530,11 @@ func (p *postgres) CreateReception(ctx context.Context, pvzID uuid.UUID) (model.
 		PVZID: pvzID,
 	}
 	err = tx.QueryRow(ctx, query, args...).Scan(&reception.ID, &reception.Datetime, &reception.Status)
-	if err != nil {
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolationCode { // Concurrent request has created reception.
+		return model.Reception{}, repository.ErrReceptionInProgress
+	} else if err != nil { // Some error.
 		return model.Reception{}, fmt.Errorf("inserting reception: %w", err)
 	}
 
@@ -344,6 +554,11 @@ func (p *postgres) CloseLastReception(ctx context.Context, pvzID uuid.UUID) (mod
 	}
 	defer tx.Rollback(ctx)
 
+	// Reception in progress can be closed even if PVZ is not active anymore.
+	if _, err := p.lockPVZ(ctx, tx, pvzID); err != nil {
+		return model.Reception{}, err
+	}
+
 	// Check if there is a reception with "in_progress" status.
 	query, args, err := p.builder.
 		Select("receptions.id").
@@ -394,13 +609,17 @@ func (p *postgres) CloseLastReception(ctx context.Context, pvzID uuid.UUID) (mod
 }
 
 // AddProduct implements repository.Repository.
-func (p *postgres) AddProduct(ctx context.Context, pvzID uuid.UUID, productType string) (model.Product, error) {
+func (p *postgres) AddProduct(ctx context.Context, pvzID uuid.UUID, newProduct model.NewProduct) (model.Product, error) {
 	tx, err := p.pool.Begin(ctx)
 	if err != nil {
 		return model.Product{}, fmt.Errorf("initiating transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	if _, err := p.lockPVZ(ctx, tx, pvzID); err != nil {
+		return model.Product{}, err
+	}
+
 	// Check if there is a reception with "in_progress" status.
 	query, args, err := p.builder.
 		Select("receptions.id").
@@ -422,28 +641,16 @@ func (p *postgres) AddProduct(ctx context.Context, pvzID uuid.UUID, productType
 
 	// "in_progress" reception was found.
 	// Check if the product type is supported.
-	query, args, err = p.builder.
-		Select("id").
-		From("product_types").
-		Where("name = ?", productType).
-		ToSql()
+	productType, err := p.GetProductType(ctx, newProduct.Type)
 	if err != nil {
-		return model.Product{}, fmt.Errorf("building query: %w", err)
-	}
-
-	var productTypeID uuid.UUID
-	err = tx.QueryRow(ctx, query, args...).Scan(&productTypeID)
-	if errors.Is(err, pgx.ErrNoRows) { // Product type was not found.
-		return model.Product{}, repository.ErrUnsupportedProductType
-	} else if err != nil { // Some error.
-		return model.Product{}, fmt.Errorf("selecting city: %w", err)
+		return model.Product{}, err
 	}
 
 	// Product type was found, so add product.
 	query, args, err = p.builder.
 		Insert("products").
-		Columns("reception_id", "product_type_id").
-		Values(receptionID, productTypeID).
+		Columns("reception_id", "product_type_id", "serial_number", "weight").
+		Values(receptionID, productType.ID, nullIfZero(newProduct.SerialNumber), nullIfZero(newProduct.Weight)).
 		Suffix("RETURNING id, datetime").
 		ToSql()
 	if err != nil {
@@ -451,14 +658,20 @@ func (p *postgres) AddProduct(ctx context.Context, pvzID uuid.UUID, productType
 	}
 
 	product := model.Product{
-		ReceptionID: receptionID,
-		Type:        productType,
+		ReceptionID:  receptionID,
+		Type:         productType.Name,
+		SerialNumber: newProduct.SerialNumber,
+		Weight:       newProduct.Weight,
 	}
 	err = tx.QueryRow(ctx, query, args...).Scan(
 		&product.ID,
 		&product.Datetime,
 	)
-	if err != nil {
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolationCode { // Product type was deleted after cache was refreshed.
+		return model.Product{}, repository.ErrUnsupportedProductType
+	} else if err != nil { // Some error.
 		return model.Product{}, fmt.Errorf("inserting product: %w", err)
 	}
 
@@ -470,14 +683,18 @@ func (p *postgres) AddProduct(ctx context.Context, pvzID uuid.UUID, productType
 	return product, nil
 }
 
-// DeleteLastProduct implements repository.Repository.
-func (p *postgres) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
+// AddProducts implements repository.Repository.
+func (p *postgres) AddProducts(ctx context.Context, pvzID uuid.UUID, newProducts []model.NewProduct) ([]model.Product, []int, error) {
 	tx, err := p.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("initiating transaction: %w", err)
+		return nil, nil, fmt.Errorf("initiating transaction: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
+	if _, err := p.lockPVZ(ctx, tx, pvzID); err != nil {
+		return nil, nil, err
+	}
+
 	// Check if there is a reception with "in_progress" status.
 	query, args, err := p.builder.
 		Select("receptions.id").
@@ -486,49 +703,278 @@ func (p *postgres) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
 		Where("pvz_id = ? AND status = ?", pvzID, _inProgressStatus).
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("building query: %w", err)
+		return nil, nil, fmt.Errorf("building query: %w", err)
 	}
 
 	var receptionID uuid.UUID
 	err = tx.QueryRow(ctx, query, args...).Scan(&receptionID)
 	if errors.Is(err, pgx.ErrNoRows) { // "in_progress" reception was not found.
-		return repository.ErrNoReceptionInProgress
+		return nil, nil, repository.ErrNoReceptionInProgress
 	} else if err != nil { // Some error.
-		return fmt.Errorf("selecting reception: %w", err)
+		return nil, nil, fmt.Errorf("selecting reception: %w", err)
+	}
+
+	// "in_progress" reception was found.
+	// Each distinct product type is looked up once: in cache or,
+	// if it is missing there, in database.
+	productTypes := make(map[string]model.ProductType)
+	for _, newProduct := range newProducts {
+		if _, ok := productTypes[newProduct.Type]; ok {
+			continue
+		}
+
+		productType, err := p.GetProductType(ctx, newProduct.Type)
+		if err != nil && !errors.Is(err, repository.ErrUnsupportedProductType) {
+			return nil, nil, err
+		}
+		productTypes[newProduct.Type] = productType // Zero value for unsupported type.
+	}
+
+	// Insert supported products in one round trip.
+	var (
+		batch    pgx.Batch
+		products []model.Product
+		rejected []int
+	)
+	for i, newProduct := range newProducts {
+		productType := productTypes[newProduct.Type]
+		if productType.ID == uuid.Nil { // Product type was not found.
+			rejected = append(rejected, i)
+			continue
+		}
+
+		query, args, err = p.builder.
+			Insert("products").
+			Columns("reception_id", "product_type_id", "serial_number", "weight").
+			Values(receptionID, productType.ID, nullIfZero(newProduct.SerialNumber), nullIfZero(newProduct.Weight)).
+			Suffix("RETURNING id, datetime").
+			ToSql()
+		if err != nil {
+			return nil, nil, fmt.Errorf("building query: %w", err)
+		}
+
+		batch.Queue(query, args...)
+		products = append(products, model.Product{
+			ReceptionID:  receptionID,
+			Type:         productType.Name,
+			SerialNumber: newProduct.SerialNumber,
+			Weight:       newProduct.Weight,
+		})
+	}
+
+	results := tx.SendBatch(ctx, &batch)
+	for i := range products {
+		err := results.QueryRow().Scan(
+			&products[i].ID,
+			&products[i].Datetime,
+		)
+		if err != nil {
+			results.Close()
+
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolationCode { // Product type was deleted after cache was refreshed.
+				return nil, nil, repository.ErrUnsupportedProductType
+			}
+			return nil, nil, fmt.Errorf("inserting product: %w", err)
+		}
+	}
+
+	if err := results.Close(); err != nil {
+		return nil, nil, fmt.Errorf("closing batch: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("committing transaction: %w", err)
 	}
 
-	// "in_progress" reception was found, so delete last product.
-	subQuery, subArgs, err := p.builder.
-		Select("id").
-		From("products").
-		OrderBy("datetime DESC").
-		Limit(1).
+	return products, rejected, nil
+}
+
+// DeleteLastProducts implements repository.Repository.
+func (p *postgres) DeleteLastProducts(ctx context.Context, pvzID uuid.UUID, count int) ([]model.Product, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := p.lockPVZ(ctx, tx, pvzID); err != nil {
+		return nil, err
+	}
+
+	// Check if there is a reception with "in_progress" status.
+	query, args, err := p.builder.
+		Select("receptions.id").
+		From("receptions").
+		Join("pvzs ON receptions.pvz_id = pvzs.id").
+		Where("pvz_id = ? AND status = ?", pvzID, _inProgressStatus).
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("building subquery: %w", err)
+		return nil, fmt.Errorf("building query: %w", err)
 	}
 
+	var receptionID uuid.UUID
+	err = tx.QueryRow(ctx, query, args...).Scan(&receptionID)
+	if errors.Is(err, pgx.ErrNoRows) { // "in_progress" reception was not found.
+		return nil, repository.ErrNoReceptionInProgress
+	} else if err != nil { // Some error.
+		return nil, fmt.Errorf("selecting reception: %w", err)
+	}
+
+	// "in_progress" reception was found, so delete its last products.
 	query, args, err = p.builder.
 		Delete("products").
-		Where("id IN ("+subQuery+")", subArgs...).
+		Where(
+			"id IN (SELECT id FROM products WHERE reception_id = ? ORDER BY seq DESC LIMIT ?)",
+			receptionID, count,
+		).
+		Suffix("RETURNING id, reception_id, datetime, (SELECT name FROM product_types WHERE id = product_type_id), COALESCE(serial_number, ''), COALESCE(weight, 0), seq").
 		ToSql()
 	if err != nil {
-		return fmt.Errorf("building query: %w", err)
+		return nil, fmt.Errorf("building query: %w", err)
 	}
 
-	deleteTag, err := tx.Exec(ctx, query, args...)
+	rows, err := tx.Query(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("deleting product: %w", err)
+		return nil, fmt.Errorf("deleting products: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		products []model.Product
+		seqs     = make(map[uuid.UUID]int64)
+	)
+	for rows.Next() {
+		var (
+			product model.Product
+			seq     int64
+		)
+		if err := rows.Scan(
+			&product.ID,
+			&product.ReceptionID,
+			&product.Datetime,
+			&product.Type,
+			&product.SerialNumber,
+			&product.Weight,
+			&seq,
+		); err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+
+		products = append(products, product)
+		seqs[product.ID] = seq
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
 	}
 
-	if deleteTag.RowsAffected() == 0 {
-		return repository.ErrReceptionIsEmpty
+	// Deletion is atomic: nothing is deleted unless there are enough products.
+	if len(products) == 0 {
+		return nil, repository.ErrReceptionIsEmpty
+	} else if len(products) < count {
+		return nil, repository.ErrNotEnoughProducts
 	}
 
+	// RETURNING doesn't keep order, so restore LIFO order.
+	slices.SortFunc(products, func(a, b model.Product) int {
+		return cmp.Compare(seqs[b.ID], seqs[a.ID])
+	})
+
 	err = tx.Commit(ctx)
 	if err != nil {
-		return fmt.Errorf("committing transaction: %w", err)
+		return nil, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return products, nil
+}
+
+// lockPVZ locks PVZ row until the end of transaction, so operations with
+// receptions and products of one PVZ are serialized, and returns PVZ status.
+// It returns repository.ErrPVZNotFound if PVZ doesn't exist.
+func (p *postgres) lockPVZ(ctx context.Context, tx pgx.Tx, pvzID uuid.UUID) (string, error) {
+	query, args, err := p.builder.
+		Select("status").
+		From("pvzs").
+		Where("id = ?", pvzID).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("building query: %w", err)
+	}
+
+	var status string
+	err = tx.QueryRow(ctx, query, args...).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) { // PVZ was not found.
+		return "", repository.ErrPVZNotFound
+	} else if err != nil { // Some error.
+		return "", fmt.Errorf("locking pvz: %w", err)
+	}
+
+	return status, nil
+}
+
+// _pvzColumns are scanned by scanPVZ, they expect pvzs aliased as p and cities as c.
+var _pvzColumns = []string{
+	"p.id",
+	"p.registration_date",
+	"c.name",
+	"p.address",
+	"p.latitude",
+	"p.longitude",
+	"p.working_hours",
+	"COALESCE(p.capacity, 0)",
+	"p.status",
+}
+
+// scanPVZ scans PVZ selected by _pvzColumns.
+func scanPVZ(row pgx.Row) (model.PVZ, error) {
+	var (
+		pvz                 model.PVZ
+		latitude, longitude *float64
+	)
+
+	err := row.Scan(
+		&pvz.ID,
+		&pvz.RegistrationDate,
+		&pvz.City,
+		&pvz.Address,
+		&latitude,
+		&longitude,
+		&pvz.WorkingHours,
+		&pvz.Capacity,
+		&pvz.Status,
+	)
+	if err != nil {
+		return model.PVZ{}, err
+	}
+
+	// Constraint guarantees that both coordinates are set or both are NULL.
+	if latitude != nil && longitude != nil {
+		pvz.Location = &model.Location{
+			Latitude:  *latitude,
+			Longitude: *longitude,
+		}
+	}
+
+	return pvz, nil
+}
+
+// locationColumns stores unknown location as NULL coordinates.
+func locationColumns(location *model.Location) (*float64, *float64) {
+	if location == nil {
+		return nil, nil
+	}
+
+	return &location.Latitude, &location.Longitude
+}
+
+// workingHoursColumn stores unknown working hours as empty list, since column is not nullable.
+func workingHoursColumn(workingHours []model.WorkingHours) []model.WorkingHours {
+	if workingHours == nil {
+		return []model.WorkingHours{}
 	}
 
-	return nil
+	return workingHours
 }
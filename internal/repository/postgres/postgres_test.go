@@ -0,0 +1,613 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// Tests need PostgreSQL, they are skipped if POSTGRES_TEST_DSN is not set,
+// except in CI, where they fail.
+// Database is migrated and tests leave their rows in it.
+const _testDSNEnv = "POSTGRES_TEST_DSN"
+
+// Number of goroutines calling repository at the same time.
+const _concurrency = 20
+
+// Size of data set for benchmarks: number of PVZs with in progress
+// reception and number of products in each reception.
+const (
+	_benchPVZs           = 50
+	_benchProductsPerPVZ = 100
+)
+
+// Number of receptions closed while products are being added
+// and maximum number of products added by one goroutine to each of them.
+const (
+	_raceRounds       = 10
+	_maxAddsPerRunner = 100
+)
+
+// How long cache may take to be refreshed after notification.
+const (
+	_notificationTimeout = 5 * time.Second
+	_notificationTick    = 10 * time.Millisecond
+)
+
+type PostgresSuite struct {
+	suite.Suite
+
+	ctx  context.Context
+	repo *postgres
+}
+
+func (s *PostgresSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.repo = newTestPostgres(s.T())
+}
+
+func TestPostgresSuite(t *testing.T) {
+	suite.Run(t, &PostgresSuite{})
+}
+
+func (s *PostgresSuite) TestConcurrentCreateReception() {
+	pvz := s.createPVZ()
+
+	var (
+		wg         sync.WaitGroup
+		start      = make(chan struct{})
+		errs       = make(chan error, _concurrency)
+		receptions = make(chan model.Reception, _concurrency)
+	)
+	for range _concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			reception, err := s.repo.CreateReception(s.ctx, pvz.ID)
+			if err != nil {
+				errs <- err
+				return
+			}
+			receptions <- reception
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+	close(receptions)
+
+	s.Require().Len(receptions, 1, "Exactly one reception must be created")
+	for err := range errs {
+		s.Require().True(errors.Is(err, repository.ErrReceptionInProgress), "Unexpected error: %v", err)
+	}
+}
+
+func (s *PostgresSuite) TestAddProductRacesCloseLastReception() {
+	pvz := s.createPVZ()
+
+	for range _raceRounds {
+		reception, err := s.repo.CreateReception(s.ctx, pvz.ID)
+		s.Require().NoError(err, "Failed to create reception")
+
+		var (
+			wg      sync.WaitGroup
+			added   = make(chan struct{}, 1)
+			errs    = make(chan error, _concurrency+1)
+			closing = make(chan int, 1)
+		)
+
+		// Adders add products until reception is closed.
+		for range _concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for range _maxAddsPerRunner {
+					_, err := s.repo.AddProduct(s.ctx, pvz.ID, model.NewProduct{Type: "электроника"})
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					select {
+					case added <- struct{}{}:
+					default:
+					}
+				}
+			}()
+		}
+
+		// Closer closes reception as soon as products are being added
+		// and remembers how many products reception has right after that.
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-added
+
+			if _, err := s.repo.CloseLastReception(s.ctx, pvz.ID); err != nil {
+				errs <- err
+				closing <- -1
+				return
+			}
+
+			count, err := s.countProducts(reception.ID)
+			if err != nil {
+				errs <- err
+			}
+			closing <- count
+		}()
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			s.Require().True(errors.Is(err, repository.ErrNoReceptionInProgress), "Unexpected error: %v", err)
+		}
+
+		// No product may get into reception after it was closed.
+		count, err := s.countProducts(reception.ID)
+		s.Require().NoError(err, "Failed to count products")
+		s.Require().Equal(<-closing, count, "Product was added to closed reception")
+	}
+}
+
+func (s *PostgresSuite) TestDeleteLastProductsScopedToPVZ() {
+	pvzs := []model.PVZ{s.createPVZ(), s.createPVZ()}
+	added := make([][]model.Product, len(pvzs))
+	for i, pvz := range pvzs {
+		_, err := s.repo.CreateReception(s.ctx, pvz.ID)
+		s.Require().NoError(err, "Failed to create reception")
+
+		added[i], _, err = s.repo.AddProducts(s.ctx, pvz.ID, slices.Repeat([]model.NewProduct{{Type: "электроника"}}, 3))
+		s.Require().NoError(err, "Failed to add products")
+	}
+
+	// Products of the other PVZ were added later, but they must not be deleted.
+	deleted, err := s.repo.DeleteLastProducts(s.ctx, pvzs[0].ID, 2)
+	s.Require().NoError(err, "Failed to delete products")
+	s.Require().Equal([]uuid.UUID{added[0][2].ID, added[0][1].ID}, productIDs(deleted), "Products must be deleted in LIFO order")
+
+	count, err := s.countProducts(added[1][0].ReceptionID)
+	s.Require().NoError(err, "Failed to count products")
+	s.Require().Equal(len(added[1]), count, "Products of other PVZ must be kept")
+
+	// Nothing is deleted if there are fewer products than requested.
+	_, err = s.repo.DeleteLastProducts(s.ctx, pvzs[0].ID, 2)
+	s.Require().ErrorIs(err, repository.ErrNotEnoughProducts)
+
+	count, err = s.countProducts(added[0][0].ReceptionID)
+	s.Require().NoError(err, "Failed to count products")
+	s.Require().Equal(1, count, "Products must be kept if there are not enough of them")
+}
+
+func (s *PostgresSuite) TestCityLifecycle() {
+	code := uuid.NewString()
+	city, err := s.repo.CreateCity(s.ctx, model.City{
+		Code:  code,
+		Name:  "Город " + code,
+		Names: map[string]string{"en": "City " + code},
+	})
+	s.Require().NoError(err, "Failed to create city")
+
+	_, err = s.repo.CreateCity(s.ctx, model.City{Code: "other " + code, Name: city.Name})
+	s.Require().ErrorIs(err, repository.ErrCityExists)
+
+	_, err = s.repo.CreateCity(s.ctx, model.City{Code: code, Name: "Другой город " + code})
+	s.Require().ErrorIs(err, repository.ErrCityExists)
+
+	pvz, err := s.repo.CreatePVZ(s.ctx, model.PVZ{City: city.Names["en"], Status: model.PVZStatusActive})
+	s.Require().NoError(err, "PVZ must be created in new city")
+	s.Require().Equal(city.Name, pvz.City, "PVZ must have default name of city")
+
+	err = s.repo.DeleteCity(s.ctx, city.ID)
+	s.Require().ErrorIs(err, repository.ErrCityHasPVZs)
+
+	deactivated, err := s.repo.DeactivateCity(s.ctx, city.ID)
+	s.Require().NoError(err, "Failed to deactivate city")
+	s.Require().False(deactivated.IsActive)
+
+	_, err = s.repo.CreatePVZ(s.ctx, model.PVZ{City: city.Name, Status: model.PVZStatusActive})
+	s.Require().ErrorIs(err, repository.ErrUnsupportedCity)
+
+	_, err = s.repo.CreateCity(s.ctx, model.City{Code: "other-" + code, Name: city.Name})
+	s.Require().ErrorIs(err, repository.ErrCityExists, "City must not be activated with other code")
+
+	activated, err := s.repo.CreateCity(s.ctx, city)
+	s.Require().NoError(err, "Failed to activate city")
+	s.Require().Equal(city.ID, activated.ID, "City must be activated, not created again")
+	s.Require().True(activated.IsActive)
+}
+
+func (s *PostgresSuite) TestProductTypeLifecycle() {
+	code := uuid.NewString()
+	productType, err := s.repo.CreateProductType(s.ctx, model.ProductType{
+		Code:                 code,
+		Name:                 "Тип " + code,
+		SerialNumberRequired: true,
+		MaxWeight:            1000,
+	})
+	s.Require().NoError(err, "Failed to create product type")
+
+	_, err = s.repo.CreateProductType(s.ctx, model.ProductType{Code: code, Name: "Другой тип " + code})
+	s.Require().ErrorIs(err, repository.ErrProductTypeExists)
+
+	// Zero max weight means no limit.
+	productType.MaxWeight = 0
+	productType.Names = map[string]string{"en": "Type " + code}
+	updated, err := s.repo.UpdateProductType(s.ctx, productType)
+	s.Require().NoError(err, "Failed to update product type")
+	s.Require().Equal(productType, updated)
+
+	pvz := s.createPVZ()
+	_, err = s.repo.CreateReception(s.ctx, pvz.ID)
+	s.Require().NoError(err, "Failed to create reception")
+
+	product, err := s.repo.AddProduct(s.ctx, pvz.ID, model.NewProduct{
+		Type:         code,
+		SerialNumber: "SN-1",
+		Weight:       1500,
+	})
+	s.Require().NoError(err, "Failed to add product")
+	s.Require().Equal(productType.Name, product.Type, "Product must have default name of type")
+
+	err = s.repo.DeleteProductType(s.ctx, productType.ID)
+	s.Require().ErrorIs(err, repository.ErrProductTypeInUse)
+
+	deleted, err := s.repo.DeleteLastProducts(s.ctx, pvz.ID, 1)
+	s.Require().NoError(err, "Failed to delete product")
+	s.Require().Equal(product.SerialNumber, deleted[0].SerialNumber)
+	s.Require().Equal(product.Weight, deleted[0].Weight)
+
+	err = s.repo.DeleteProductType(s.ctx, productType.ID)
+	s.Require().NoError(err, "Product type without products must be deleted")
+}
+
+func (s *PostgresSuite) TestLookupKeysAreUnique() {
+	code := uuid.NewString()
+
+	// Translation can't find another product type by its code.
+	_, err := s.repo.CreateProductType(s.ctx, model.ProductType{
+		Code:  code,
+		Name:  "Тип " + code,
+		Names: map[string]string{"en": "CLOTHES"},
+	})
+	s.Require().ErrorIs(err, repository.ErrProductTypeExists)
+
+	productType, err := s.repo.CreateProductType(s.ctx, model.ProductType{Code: code, Name: "Тип " + code})
+	s.Require().NoError(err, "Failed to create product type")
+
+	// Names differing only in case find the same product type.
+	_, err = s.repo.CreateProductType(s.ctx, model.ProductType{Code: "other-" + code, Name: "ТИП " + code})
+	s.Require().ErrorIs(err, repository.ErrProductTypeExists)
+
+	productType.Names = map[string]string{"en": "Электроника"}
+	_, err = s.repo.UpdateProductType(s.ctx, productType)
+	s.Require().ErrorIs(err, repository.ErrProductTypeExists)
+
+	_, err = s.repo.CreateCity(s.ctx, model.City{Code: code, Name: "Город " + code, Names: map[string]string{"en": "moscow"}})
+	s.Require().ErrorIs(err, repository.ErrCityExists)
+}
+
+func (s *PostgresSuite) TestReferenceCacheRefreshedByNotification() {
+	// Changes are made bypassing repository, like another replica would do.
+	name := "Город " + uuid.NewString()
+	_, err := s.repo.pool.Exec(s.ctx, "INSERT INTO cities (code, name) VALUES ($1, $1)", name)
+	s.Require().NoError(err, "Failed to insert city")
+
+	s.Require().Eventually(func() bool {
+		city, ok := s.repo.refs.city(name)
+		return ok && city.IsActive
+	}, _notificationTimeout, _notificationTick, "Inserted city must get into cache")
+
+	_, err = s.repo.pool.Exec(s.ctx, "UPDATE cities SET is_active = FALSE WHERE name = $1", name)
+	s.Require().NoError(err, "Failed to deactivate city")
+
+	s.Require().Eventually(func() bool {
+		city, ok := s.repo.refs.city(name)
+		return ok && !city.IsActive
+	}, _notificationTimeout, _notificationTick, "Deactivated city must be updated in cache")
+
+	_, err = s.repo.CreatePVZ(s.ctx, model.PVZ{City: name, Status: model.PVZStatusActive})
+	s.Require().ErrorIs(err, repository.ErrUnsupportedCity)
+}
+
+func (s *PostgresSuite) TestPVZDetailsAndStatus() {
+	pvz, err := s.repo.CreatePVZ(s.ctx, model.PVZ{
+		City:     "msk",
+		Address:  "ул. Льва Толстого, 16",
+		Location: &model.Location{Latitude: 55.733842, Longitude: 37.588144},
+		WorkingHours: []model.WorkingHours{
+			{Weekday: 1, Opens: "09:00", Closes: "21:00"},
+			{Weekday: 6, Opens: "10:00", Closes: "18:00"},
+		},
+		Capacity: 500,
+		Status:   model.PVZStatusSuspended,
+	})
+	s.Require().NoError(err, "Failed to create PVZ")
+
+	got, err := s.repo.GetPVZ(s.ctx, pvz.ID)
+	s.Require().NoError(err, "Failed to get PVZ")
+	s.Require().Equal(pvz.ID, got.ID)
+	s.Require().Equal(pvz.Location, got.Location)
+	s.Require().Equal(pvz.WorkingHours, got.WorkingHours)
+	s.Require().Equal(pvz.Capacity, got.Capacity)
+	s.Require().Equal(model.PVZStatusSuspended, got.Status)
+
+	_, err = s.repo.CreateReception(s.ctx, pvz.ID)
+	s.Require().ErrorIs(err, repository.ErrPVZNotActive)
+
+	// Unknown details are cleared by update.
+	updated, err := s.repo.UpdatePVZ(s.ctx, model.PVZ{
+		ID:      pvz.ID,
+		Address: pvz.Address,
+		Status:  model.PVZStatusActive,
+	})
+	s.Require().NoError(err, "Failed to update PVZ")
+	s.Require().Equal(pvz.City, updated.City, "City must not be changed")
+	s.Require().Nil(updated.Location)
+	s.Require().Empty(updated.WorkingHours)
+	s.Require().Zero(updated.Capacity)
+
+	_, err = s.repo.CreateReception(s.ctx, pvz.ID)
+	s.Require().NoError(err, "Reception must be created in active PVZ")
+
+	// Reception in progress is finished after PVZ was suspended.
+	_, err = s.repo.UpdatePVZ(s.ctx, model.PVZ{ID: pvz.ID, Status: model.PVZStatusSuspended})
+	s.Require().NoError(err, "Failed to suspend PVZ")
+
+	reception, err := s.repo.CloseLastReception(s.ctx, pvz.ID)
+	s.Require().NoError(err, "Reception must be closed in suspended PVZ")
+	s.Require().Equal(model.ReceptionStatusClose, reception.Status)
+
+	_, err = s.repo.UpdatePVZ(s.ctx, model.PVZ{ID: uuid.New(), Status: model.PVZStatusClosed})
+	s.Require().ErrorIs(err, repository.ErrPVZNotFound)
+}
+
+func (s *PostgresSuite) TestRevokeTokens() {
+	owner := model.Identity{UserID: uuid.New(), Role: model.RoleEmployee}
+	tokenHash := uuid.NewString()
+	err := s.repo.CreateRefreshToken(s.ctx, tokenHash, owner, time.Now().Add(time.Hour))
+	s.Require().NoError(err, "Failed to create refresh token")
+
+	// Refresh token of another user revokes nothing.
+	other := model.Identity{UserID: uuid.New(), TokenID: uuid.New(), ExpiresAt: time.Now().Add(time.Hour)}
+	err = s.repo.RevokeTokens(s.ctx, other, tokenHash)
+	s.Require().ErrorIs(err, repository.ErrRefreshTokenNotFound)
+
+	revoked, err := s.repo.IsAccessTokenRevoked(s.ctx, other.TokenID)
+	s.Require().NoError(err, "Failed to check access token")
+	s.Require().False(revoked, "Access token must not be revoked if refresh token is rejected")
+
+	owner.TokenID = uuid.New()
+	owner.ExpiresAt = time.Now().Add(time.Hour)
+	s.Require().NoError(s.repo.RevokeTokens(s.ctx, owner, tokenHash), "Failed to revoke tokens")
+
+	revoked, err = s.repo.IsAccessTokenRevoked(s.ctx, owner.TokenID)
+	s.Require().NoError(err, "Failed to check access token")
+	s.Require().True(revoked)
+
+	_, err = s.repo.RotateRefreshToken(s.ctx, tokenHash, uuid.NewString(), time.Now().Add(time.Hour))
+	s.Require().ErrorIs(err, repository.ErrRefreshTokenNotFound, "Revoked refresh token must not be rotated")
+}
+
+func (s *PostgresSuite) TestGetPVZPaginationFilters() {
+	// PVZs are created in new city, so other tests don't affect the listing.
+	code := uuid.NewString()
+	city, err := s.repo.CreateCity(s.ctx, model.City{Code: code, Name: "Город " + code})
+	s.Require().NoError(err, "Failed to create city")
+
+	withoutReceptions, err := s.repo.CreatePVZ(s.ctx, model.PVZ{City: city.Name, Status: model.PVZStatusActive})
+	s.Require().NoError(err, "Failed to create PVZ")
+
+	withReceptions, err := s.repo.CreatePVZ(s.ctx, model.PVZ{City: city.Name, Status: model.PVZStatusActive})
+	s.Require().NoError(err, "Failed to create PVZ")
+
+	closed, err := s.repo.CreateReception(s.ctx, withReceptions.ID)
+	s.Require().NoError(err, "Failed to create reception")
+	_, err = s.repo.CloseLastReception(s.ctx, withReceptions.ID)
+	s.Require().NoError(err, "Failed to close reception")
+
+	inProgress, err := s.repo.CreateReception(s.ctx, withReceptions.ID)
+	s.Require().NoError(err, "Failed to create reception")
+
+	// PVZs without receptions match only if receptions are not filtered.
+	filter := model.PVZFilter{City: city.Name}
+	pvzs, err := s.repo.GetPVZPagination(s.ctx, filter, nil, 10)
+	s.Require().NoError(err, "Failed to get PVZs")
+	s.Require().Equal([]uuid.UUID{withoutReceptions.ID, withReceptions.ID}, pvzIDs(pvzs))
+	s.Require().Empty(pvzs[0].Receptions)
+	s.Require().Equal([]uuid.UUID{closed.ID, inProgress.ID}, receptionIDs(pvzs[1].Receptions), "Closed reception must be listed too")
+
+	count, err := s.repo.CountPVZ(s.ctx, filter)
+	s.Require().NoError(err, "Failed to count PVZs")
+	s.Require().Equal(2, count)
+
+	// PVZ with several matching receptions is counted once.
+	filter.StartDate = time.Now().Add(-time.Hour)
+	filter.EndDate = time.Now().Add(time.Hour)
+	pvzs, err = s.repo.GetPVZPagination(s.ctx, filter, nil, 1)
+	s.Require().NoError(err, "Failed to get PVZs")
+	s.Require().Equal([]uuid.UUID{withReceptions.ID}, pvzIDs(pvzs))
+	s.Require().Len(pvzs[0].Receptions, 2)
+
+	count, err = s.repo.CountPVZ(s.ctx, filter)
+	s.Require().NoError(err, "Failed to count PVZs")
+	s.Require().Equal(1, count)
+
+	// Only receptions with the status are listed.
+	filter.Status = model.ReceptionStatusClose
+	pvzs, err = s.repo.GetPVZPagination(s.ctx, filter, nil, 10)
+	s.Require().NoError(err, "Failed to get PVZs")
+	s.Require().Equal([]uuid.UUID{withReceptions.ID}, pvzIDs(pvzs))
+	s.Require().Equal([]uuid.UUID{closed.ID}, receptionIDs(pvzs[0].Receptions))
+
+	// PVZs of other cities don't match.
+	filter = model.PVZFilter{City: "Город " + uuid.NewString()}
+	pvzs, err = s.repo.GetPVZPagination(s.ctx, filter, nil, 10)
+	s.Require().NoError(err, "Failed to get PVZs")
+	s.Require().Empty(pvzs)
+}
+
+// countProducts doesn't fail the test, so it can be called from goroutines.
+func (s *PostgresSuite) countProducts(receptionID uuid.UUID) (int, error) {
+	var count int
+	err := s.repo.pool.QueryRow(s.ctx, "SELECT COUNT(*) FROM products WHERE reception_id = $1", receptionID).Scan(&count)
+
+	return count, err
+}
+
+func pvzIDs(pvzs []model.PVZ) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(pvzs))
+	for _, pvz := range pvzs {
+		ids = append(ids, pvz.ID)
+	}
+
+	return ids
+}
+
+func receptionIDs(receptions []model.Reception) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(receptions))
+	for _, reception := range receptions {
+		ids = append(ids, reception.ID)
+	}
+
+	return ids
+}
+
+func productIDs(products []model.Product) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(products))
+	for _, product := range products {
+		ids = append(ids, product.ID)
+	}
+
+	return ids
+}
+
+func (s *PostgresSuite) createPVZ() model.PVZ {
+	pvz, err := s.repo.CreatePVZ(s.ctx, model.PVZ{City: "msk", Status: model.PVZStatusActive})
+	s.Require().NoError(err, "Failed to create PVZ")
+
+	return pvz
+}
+
+func BenchmarkGetPVZPagination(b *testing.B) {
+	ctx := context.Background()
+	repo := newTestPostgres(b)
+
+	receptionIDs := make([]uuid.UUID, 0, _benchPVZs)
+	products := slices.Repeat([]model.NewProduct{{Type: "электроника"}}, _benchProductsPerPVZ)
+	for range _benchPVZs {
+		pvz, err := repo.CreatePVZ(ctx, model.PVZ{City: "Москва", Status: model.PVZStatusActive})
+		if err != nil {
+			b.Fatalf("Failed to create PVZ: %v", err)
+		}
+
+		reception, err := repo.CreateReception(ctx, pvz.ID)
+		if err != nil {
+			b.Fatalf("Failed to create reception: %v", err)
+		}
+
+		if _, _, err := repo.AddProducts(ctx, pvz.ID, products); err != nil {
+			b.Fatalf("Failed to add products: %v", err)
+		}
+
+		receptionIDs = append(receptionIDs, reception.ID)
+	}
+
+	filter := model.PVZFilter{
+		StartDate: time.Now().Add(-time.Hour),
+		EndDate:   time.Now().Add(time.Hour),
+	}
+
+	b.Run("page", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := repo.GetPVZPagination(ctx, filter, nil, _benchPVZs); err != nil {
+				b.Fatalf("Failed to get PVZs: %v", err)
+			}
+		}
+	})
+
+	// Query per reception is how products were selected before.
+	b.Run("products per reception", func(b *testing.B) {
+		for b.Loop() {
+			withTx(b, repo, func(tx pgx.Tx) error {
+				for _, id := range receptionIDs {
+					if _, err := repo.selectProducts(ctx, tx, []uuid.UUID{id}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		}
+	})
+
+	b.Run("products in one query", func(b *testing.B) {
+		for b.Loop() {
+			withTx(b, repo, func(tx pgx.Tx) error {
+				_, err := repo.selectProducts(ctx, tx, receptionIDs)
+				return err
+			})
+		}
+	})
+}
+
+// newTestPostgres connects to database from POSTGRES_TEST_DSN and migrates it.
+func newTestPostgres(tb testing.TB) *postgres {
+	tb.Helper()
+
+	// CI always provides database, so tests must not be skipped there silently.
+	dsn := os.Getenv(_testDSNEnv)
+	if dsn == "" && os.Getenv("CI") != "" {
+		tb.Fatalf("%s must be set in CI", _testDSNEnv)
+	} else if dsn == "" {
+		tb.Skipf("%s is not set", _testDSNEnv)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo, err := NewPostgres(ctx, config.DBConfig{
+		PostgresDSN:       dsn,
+		GooseDriver:       "postgres",
+		GooseMigrationDir: "../../../migrations",
+	})
+	if err != nil {
+		cancel()
+		tb.Fatalf("Failed to create repository: %v", err)
+	}
+	tb.Cleanup(repo.pool.Close)
+	// Cleanups run in reverse order, so listener stops before pool is closed.
+	tb.Cleanup(cancel)
+
+	return repo
+}
+
+func withTx(tb testing.TB, repo *postgres, f func(tx pgx.Tx) error) {
+	tb.Helper()
+
+	ctx := context.Background()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		tb.Fatalf("Failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := f(tx); err != nil {
+		tb.Fatalf("Failed to select products: %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// GetProductTypes implements repository.Repository.
+func (p *postgres) GetProductTypes(_ context.Context) ([]model.ProductType, error) {
+	return p.refs.allProductTypes(), nil
+}
+
+// GetProductType implements repository.Repository.
+// Product type missing from cache could be created by another replica
+// which notification is not received yet, so it is looked up in database
+// by the key alone.
+func (p *postgres) GetProductType(ctx context.Context, key string) (model.ProductType, error) {
+	if productType, ok := p.refs.productType(key); ok {
+		return productType, nil
+	}
+
+	query, args, err := p.builder.
+		Select(
+			"r.id",
+			"r.code",
+			"r.name",
+			"r.serial_number_required",
+			"COALESCE(r.max_weight, 0)",
+		).
+		Column(translationsColumn(_productTypesTable)).
+		From("product_types AS r").
+		Where(lookupKeyCondition(_productTypesTable, key)).
+		ToSql()
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var productType model.ProductType
+	err = p.pool.QueryRow(ctx, query, args...).Scan(
+		&productType.ID,
+		&productType.Code,
+		&productType.Name,
+		&productType.SerialNumberRequired,
+		&productType.MaxWeight,
+		&productType.Names,
+	)
+	if errors.Is(err, pgx.ErrNoRows) { // Product type was not found.
+		return model.ProductType{}, repository.ErrUnsupportedProductType
+	} else if err != nil { // Some error.
+		return model.ProductType{}, fmt.Errorf("selecting product type: %w", err)
+	}
+
+	return productType, nil
+}
+
+// selectProductTypes selects all product types with their translations from database.
+func (p *postgres) selectProductTypes(ctx context.Context) ([]model.ProductType, error) {
+	query, args, err := p.builder.
+		Select(
+			"id",
+			"code",
+			"name",
+			"serial_number_required",
+			"COALESCE(max_weight, 0)",
+		).
+		From("product_types").
+		OrderBy("name").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting product types: %w", err)
+	}
+	defer rows.Close()
+
+	productTypes := make([]model.ProductType, 0)
+	for rows.Next() {
+		var productType model.ProductType
+
+		err := rows.Scan(
+			&productType.ID,
+			&productType.Code,
+			&productType.Name,
+			&productType.SerialNumberRequired,
+			&productType.MaxWeight,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+
+		productTypes = append(productTypes, productType)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
+	}
+
+	translations, err := p.selectTranslations(ctx, _productTypesTable)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range productTypes {
+		productTypes[i].Names = translations[productTypes[i].ID]
+	}
+
+	return productTypes, nil
+}
+
+// CreateProductType implements repository.Repository.
+func (p *postgres) CreateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.lockReferenceTable(ctx, tx, _productTypesTable); err != nil {
+		return model.ProductType{}, err
+	}
+
+	taken, err := p.lookupKeysTaken(ctx, tx, _productTypesTable, uuid.Nil, productType.LookupKeys())
+	if err != nil {
+		return model.ProductType{}, err
+	} else if taken { // Another product type has the same code or name.
+		return model.ProductType{}, repository.ErrProductTypeExists
+	}
+
+	query, args, err := p.builder.
+		Insert("product_types").
+		Columns("code", "name", "serial_number_required", "max_weight").
+		Values(productType.Code, productType.Name, productType.SerialNumberRequired, nullIfZero(productType.MaxWeight)).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var productTypeID uuid.UUID
+	err = tx.QueryRow(ctx, query, args...).Scan(&productTypeID)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolationCode { // Product type with the code or name exists.
+		return model.ProductType{}, repository.ErrProductTypeExists
+	} else if err != nil { // Some error.
+		return model.ProductType{}, fmt.Errorf("inserting product type: %w", err)
+	}
+
+	err = p.replaceTranslations(ctx, tx, _productTypesTable, productTypeID, productType.Names)
+	if err != nil {
+		return model.ProductType{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return p.reloadedProductType(ctx, productTypeID)
+}
+
+// UpdateProductType implements repository.Repository.
+func (p *postgres) UpdateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := p.lockReferenceTable(ctx, tx, _productTypesTable); err != nil {
+		return model.ProductType{}, err
+	}
+
+	taken, err := p.lookupKeysTaken(ctx, tx, _productTypesTable, productType.ID, productType.LookupKeys())
+	if err != nil {
+		return model.ProductType{}, err
+	} else if taken { // Another product type has the same code or name.
+		return model.ProductType{}, repository.ErrProductTypeExists
+	}
+
+	query, args, err := p.builder.
+		Update("product_types").
+		Set("code", productType.Code).
+		Set("name", productType.Name).
+		Set("serial_number_required", productType.SerialNumberRequired).
+		Set("max_weight", nullIfZero(productType.MaxWeight)).
+		Where("id = ?", productType.ID).
+		ToSql()
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("building query: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, query, args...)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolationCode { // Product type with the code or name exists.
+		return model.ProductType{}, repository.ErrProductTypeExists
+	} else if err != nil { // Some error.
+		return model.ProductType{}, fmt.Errorf("updating product type: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 { // Product type was not found.
+		return model.ProductType{}, repository.ErrProductTypeNotFound
+	}
+
+	err = p.replaceTranslations(ctx, tx, _productTypesTable, productType.ID, productType.Names)
+	if err != nil {
+		return model.ProductType{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return p.reloadedProductType(ctx, productType.ID)
+}
+
+// DeleteProductType implements repository.Repository.
+func (p *postgres) DeleteProductType(ctx context.Context, productTypeID uuid.UUID) error {
+	query, args, err := p.builder.
+		Delete("product_types").
+		Where("id = ?", productTypeID).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	tag, err := p.pool.Exec(ctx, query, args...)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _foreignKeyViolationCode { // Product type is referenced by products.
+		return repository.ErrProductTypeInUse
+	} else if err != nil { // Some error.
+		return fmt.Errorf("deleting product type: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 { // Product type was not found.
+		return repository.ErrProductTypeNotFound
+	}
+
+	if err := p.loadReferences(ctx, "product_types"); err != nil {
+		return fmt.Errorf("loading product types: %w", err)
+	}
+
+	return nil
+}
+
+// reloadedProductType reloads cache after product type was changed, so this
+// replica sees the change right away, and returns the product type from it.
+func (p *postgres) reloadedProductType(ctx context.Context, productTypeID uuid.UUID) (model.ProductType, error) {
+	if err := p.loadReferences(ctx, "product_types"); err != nil {
+		return model.ProductType{}, fmt.Errorf("loading product types: %w", err)
+	}
+
+	productType, ok := p.refs.productTypeByID(productTypeID)
+	if !ok { // Product type was deleted concurrently.
+		return model.ProductType{}, repository.ErrProductTypeNotFound
+	}
+
+	return productType, nil
+}
+
+// nullIfZero stores zero values of optional columns as NULL.
+func nullIfZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+
+	return &v
+}
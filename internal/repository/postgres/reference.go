@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+// Channel notified by triggers on cities and product_types,
+// payload is the name of the changed table.
+const _referenceChannel = "reference_data_changed"
+
+// Pause before listening again after the listening connection failed.
+const _listenRetryInterval = time.Second
+
+// referenceTable is table of cities or product types with table of their translations.
+type referenceTable struct {
+	name         string
+	translations string
+	idColumn     string // Column of translations referencing translated row.
+}
+
+var (
+	_citiesTable       = referenceTable{name: "cities", translations: "city_translations", idColumn: "city_id"}
+	_productTypesTable = referenceTable{name: "product_types", translations: "product_type_translations", idColumn: "product_type_id"}
+)
+
+// referenceCache keeps cities and product types, so hot paths don't query them.
+// It is reloaded on notifications from the database and after writes of this replica.
+type referenceCache struct {
+	mu           sync.RWMutex
+	cities       referenceSet[model.City]
+	productTypes referenceSet[model.ProductType]
+}
+
+func newReferenceCache() *referenceCache {
+	return &referenceCache{}
+}
+
+// city looks city up by code or any of its names.
+func (c *referenceCache) city(key string) (model.City, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cities.byKey(key)
+}
+
+func (c *referenceCache) cityByID(id uuid.UUID) (model.City, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.cities.byID(id)
+}
+
+func (c *referenceCache) allCities() []model.City {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.cities.items)
+}
+
+func (c *referenceCache) setCities(cities []model.City) {
+	set := newReferenceSet(cities, func(city model.City) uuid.UUID { return city.ID })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cities = set
+}
+
+// productType looks product type up by code or any of its names.
+func (c *referenceCache) productType(key string) (model.ProductType, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.productTypes.byKey(key)
+}
+
+func (c *referenceCache) productTypeByID(id uuid.UUID) (model.ProductType, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.productTypes.byID(id)
+}
+
+func (c *referenceCache) allProductTypes() []model.ProductType {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return slices.Clone(c.productTypes.items)
+}
+
+func (c *referenceCache) setProductTypes(productTypes []model.ProductType) {
+	set := newReferenceSet(productTypes, func(productType model.ProductType) uuid.UUID { return productType.ID })
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.productTypes = set
+}
+
+// referenceSet is an immutable set of cities or product types
+// indexed by ID and by lookup keys.
+type referenceSet[T interface{ LookupKeys() []string }] struct {
+	items []T
+	ids   map[uuid.UUID]int
+	keys  map[string]int
+}
+
+func newReferenceSet[T interface{ LookupKeys() []string }](items []T, id func(T) uuid.UUID) referenceSet[T] {
+	set := referenceSet[T]{
+		items: items,
+		ids:   make(map[uuid.UUID]int, len(items)),
+		keys:  make(map[string]int, len(items)),
+	}
+
+	for i, item := range items {
+		set.ids[id(item)] = i
+		for _, key := range item.LookupKeys() {
+			// Repository doesn't let keys collide, but rows changed bypassing it
+			// could, so the first row keeps the key instead of the last one.
+			if j, ok := set.keys[key]; ok {
+				if j != i {
+					zap.S().Warnf("Lookup key %q of reference data belongs to several rows", key)
+				}
+				continue
+			}
+			set.keys[key] = i
+		}
+	}
+
+	return set
+}
+
+func (s referenceSet[T]) byKey(key string) (T, bool) {
+	i, ok := s.keys[model.LookupKey(key)]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	return s.items[i], true
+}
+
+func (s referenceSet[T]) byID(id uuid.UUID) (T, bool) {
+	i, ok := s.ids[id]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	return s.items[i], true
+}
+
+// loadReferences reloads cache after the table was changed. Empty table means all of them.
+func (p *postgres) loadReferences(ctx context.Context, table string) error {
+	if table == "" || table == "cities" || table == "city_translations" {
+		cities, err := p.selectCities(ctx)
+		if err != nil {
+			return err
+		}
+		p.refs.setCities(cities)
+	}
+
+	if table == "" || table == "product_types" || table == "product_type_translations" {
+		productTypes, err := p.selectProductTypes(ctx)
+		if err != nil {
+			return err
+		}
+		p.refs.setProductTypes(productTypes)
+	}
+
+	return nil
+}
+
+// listenReferences keeps cache up to date until ctx is done.
+func (p *postgres) listenReferences(ctx context.Context) {
+	for {
+		err := p.waitReferenceChanges(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		zap.S().Errorf("Listening reference data changes: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(_listenRetryInterval):
+		}
+	}
+}
+
+// waitReferenceChanges reloads cache on notifications until connection fails.
+// Cache is reloaded right after LISTEN, so changes made while
+// nobody was listening are not lost.
+func (p *postgres) waitReferenceChanges(ctx context.Context) error {
+	poolConn, err := p.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+
+	// Listening connection is not returned to the pool.
+	conn := poolConn.Hijack()
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err := conn.Exec(ctx, "LISTEN "+_referenceChannel); err != nil {
+		return fmt.Errorf("listening: %w", err)
+	}
+
+	if err := p.loadReferences(ctx, ""); err != nil {
+		return fmt.Errorf("loading reference data: %w", err)
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("waiting for notification: %w", err)
+		}
+
+		if err := p.loadReferences(ctx, notification.Payload); err != nil {
+			return fmt.Errorf("loading reference data: %w", err)
+		}
+	}
+}
+
+// lookupKeyCondition finds row of table aliased as r by code, name or
+// translation, case insensitive. Lookup keys are unique across rows,
+// so at most one row is found.
+func lookupKeyCondition(table referenceTable, key string) squirrel.Sqlizer {
+	key = model.LookupKey(key)
+
+	translated := squirrel.
+		Select(table.idColumn).
+		From(table.translations).
+		Where("LOWER(name) = ?", key)
+
+	return squirrel.Or{
+		squirrel.Expr("LOWER(r.code) = ?", key),
+		squirrel.Expr("LOWER(r.name) = ?", key),
+		squirrel.Expr("r.id IN (?)", translated),
+	}
+}
+
+// translationsColumn selects translations of row of table aliased as r as JSON object.
+func translationsColumn(table referenceTable) squirrel.Sqlizer {
+	translations := squirrel.
+		Select("COALESCE(jsonb_object_agg(language, name), '{}')").
+		From(table.translations).
+		Where(table.idColumn + " = r.id")
+
+	return squirrel.Expr("(?)", translations)
+}
+
+// lockReferenceTable serializes writes of cities or product types until
+// the end of transaction, so lookup keys checked by lookupKeysTaken
+// can't be taken concurrently.
+func (p *postgres) lockReferenceTable(ctx context.Context, tx pgx.Tx, table referenceTable) error {
+	query, args, err := p.builder.
+		Select().
+		Column(squirrel.Expr("pg_advisory_xact_lock(hashtext(?))", table.name)).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("locking %s: %w", table.name, err)
+	}
+
+	return nil
+}
+
+// lookupKeysTaken reports whether any of keys finds row of table other than
+// the one with id by code, name or translation, case insensitive. Keys must be
+// normalized by model.LookupKey.
+func (p *postgres) lookupKeysTaken(ctx context.Context, tx pgx.Tx, table referenceTable, id uuid.UUID, keys []string) (bool, error) {
+	translations := squirrel.
+		Select("1").
+		From(table.translations).
+		Where(squirrel.NotEq{table.idColumn: id}).
+		Where("LOWER(name) = ANY(?)", keys)
+
+	rows := squirrel.
+		Select("1").
+		From(table.name).
+		Where(squirrel.NotEq{"id": id}).
+		Where("(LOWER(code) = ANY(?) OR LOWER(name) = ANY(?))", keys, keys).
+		Suffix("UNION ALL ?", translations)
+
+	query, args, err := p.builder.
+		Select().
+		Column(squirrel.Expr("EXISTS (?)", rows)).
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building query: %w", err)
+	}
+
+	var taken bool
+	if err := tx.QueryRow(ctx, query, args...).Scan(&taken); err != nil {
+		return false, fmt.Errorf("checking lookup keys: %w", err)
+	}
+
+	return taken, nil
+}
+
+// selectTranslations returns translations of cities or product types
+// grouped by ID of translated row.
+func (p *postgres) selectTranslations(ctx context.Context, table referenceTable) (map[uuid.UUID]map[string]string, error) {
+	query, args, err := p.builder.
+		Select(
+			table.idColumn,
+			"language",
+			"name",
+		).
+		From(table.translations).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("building query: %w", err)
+	}
+
+	rows, err := p.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("selecting translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[uuid.UUID]map[string]string)
+	for rows.Next() {
+		var (
+			id             uuid.UUID
+			language, name string
+		)
+
+		if err := rows.Scan(&id, &language, &name); err != nil {
+			return nil, fmt.Errorf("scanning row: %w", err)
+		}
+
+		if translations[id] == nil {
+			translations[id] = make(map[string]string)
+		}
+		translations[id][language] = name
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating rows: %w", err)
+	}
+
+	return translations, nil
+}
+
+// replaceTranslations replaces translations of city or product type with names.
+func (p *postgres) replaceTranslations(ctx context.Context, tx pgx.Tx, table referenceTable, id uuid.UUID, names map[string]string) error {
+	query, args, err := p.builder.
+		Delete(table.translations).
+		Where(table.idColumn+" = ?", id).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("deleting translations: %w", err)
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	builder := p.builder.
+		Insert(table.translations).
+		Columns(table.idColumn, "language", "name")
+	for language, name := range names {
+		builder = builder.Values(id, language, name)
+	}
+
+	query, args, err = builder.ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("inserting translations: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// CreateRefreshToken implements repository.Repository.
+func (p *postgres) CreateRefreshToken(ctx context.Context, tokenHash string, identity model.Identity, expiresAt time.Time) error {
+	query, args, err := p.builder.
+		Insert("refresh_tokens").
+		Columns("token_hash", "user_id", "role", "expires_at").
+		Values(tokenHash, identity.UserID, identity.Role, expiresAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := p.pool.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("inserting refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// RotateRefreshToken implements repository.Repository.
+func (p *postgres) RotateRefreshToken(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt time.Time) (model.Identity, error) {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Revoke the old token if it is still active.
+	query, args, err := p.builder.
+		Update("refresh_tokens").
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where("token_hash = ? AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP", oldTokenHash).
+		Suffix("RETURNING user_id, role").
+		ToSql()
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var identity model.Identity
+	err = tx.QueryRow(ctx, query, args...).Scan(&identity.UserID, &identity.Role)
+	if errors.Is(err, pgx.ErrNoRows) { // Active token was not found.
+		return model.Identity{}, repository.ErrRefreshTokenNotFound
+	} else if err != nil { // Some error.
+		return model.Identity{}, fmt.Errorf("revoking refresh token: %w", err)
+	}
+
+	// Old token was revoked, so issue the new one for the same identity.
+	query, args, err = p.builder.
+		Insert("refresh_tokens").
+		Columns("token_hash", "user_id", "role", "expires_at").
+		Values(newTokenHash, identity.UserID, identity.Role, expiresAt).
+		ToSql()
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return model.Identity{}, fmt.Errorf("inserting refresh token: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return identity, nil
+}
+
+// RevokeTokens implements repository.Repository.
+func (p *postgres) RevokeTokens(ctx context.Context, identity model.Identity, refreshTokenHash string) error {
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("initiating transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Refresh token is optional, but it must belong to the same user if given.
+	if refreshTokenHash != "" {
+		query, args, err := p.builder.
+			Update("refresh_tokens").
+			Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+			Where("token_hash = ? AND user_id = ? AND revoked_at IS NULL", refreshTokenHash, identity.UserID).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("building query: %w", err)
+		}
+
+		updateTag, err := tx.Exec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("revoking refresh token: %w", err)
+		}
+
+		if updateTag.RowsAffected() == 0 {
+			return repository.ErrRefreshTokenNotFound
+		}
+	}
+
+	// Expired tokens are rejected anyway, so there is no need to keep them.
+	query, args, err := p.builder.
+		Delete("revoked_tokens").
+		Where("expires_at < CURRENT_TIMESTAMP").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("deleting expired tokens: %w", err)
+	}
+
+	query, args, err = p.builder.
+		Insert("revoked_tokens").
+		Columns("jti", "expires_at").
+		Values(identity.TokenID, identity.ExpiresAt).
+		Suffix("ON CONFLICT (jti) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("building query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("inserting revoked token: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return fmt.Errorf("committing transaction: %w", err)
+	}
+
+	return nil
+}
+
+// IsAccessTokenRevoked implements repository.Repository.
+func (p *postgres) IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error) {
+	query, args, err := p.builder.
+		Select("1").
+		Prefix("SELECT EXISTS (").
+		From("revoked_tokens").
+		Where("jti = ?", tokenID).
+		Suffix(")").
+		ToSql()
+	if err != nil {
+		return false, fmt.Errorf("building query: %w", err)
+	}
+
+	var revoked bool
+	if err := p.pool.QueryRow(ctx, query, args...).Scan(&revoked); err != nil {
+		return false, fmt.Errorf("selecting revoked token: %w", err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// CreateUser implements repository.Repository.
+func (p *postgres) CreateUser(ctx context.Context, email, passwordHash, role string) (model.User, error) {
+	query, args, err := p.builder.
+		Insert("users").
+		Columns("email", "password_hash", "role").
+		Values(email, passwordHash, role).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return model.User{}, fmt.Errorf("building query: %w", err)
+	}
+
+	user := model.User{
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+	}
+	err = p.pool.QueryRow(ctx, query, args...).Scan(&user.ID)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == _uniqueViolationCode { // Email is already taken.
+		return model.User{}, repository.ErrUserExists
+	} else if err != nil { // Some error.
+		return model.User{}, fmt.Errorf("inserting user: %w", err)
+	}
+
+	return user, nil
+}
+
+// GetUserByEmail implements repository.Repository.
+func (p *postgres) GetUserByEmail(ctx context.Context, email string) (model.User, error) {
+	query, args, err := p.builder.
+		Select(
+			"id",
+			"email",
+			"password_hash",
+			"role",
+		).
+		From("users").
+		Where("email = ?", email).
+		ToSql()
+	if err != nil {
+		return model.User{}, fmt.Errorf("building query: %w", err)
+	}
+
+	var user model.User
+	err = p.pool.QueryRow(ctx, query, args...).Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.Role,
+	)
+	if errors.Is(err, pgx.ErrNoRows) { // User was not found.
+		return model.User{}, repository.ErrUserNotFound
+	} else if err != nil { // Some error.
+		return model.User{}, fmt.Errorf("selecting user: %w", err)
+	}
+
+	return user, nil
+}
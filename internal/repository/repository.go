@@ -10,23 +10,80 @@ import (
 )
 
 type Repository interface {
+	Ping(ctx context.Context) error
+
 	PVZRepository
 	ReceptionRepository
 	ProductRepository
+	UserRepository
+	TokenRepository
+	CityRepository
+	ProductTypeRepository
 }
 
 type PVZRepository interface {
-	CreatePVZ(ctx context.Context, city string) (model.PVZ, error)
-	GetPVZPagination(ctx context.Context, start, end time.Time, limit, offset int) ([]model.PVZ, error)
+	// CreatePVZ creates PVZ with details in city given by code or any of its names.
+	CreatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error)
+	// UpdatePVZ replaces details and status of PVZ, its city can't be changed.
+	UpdatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error)
+	// GetPVZPagination returns up to limit PVZs ordered by (registration_date, id)
+	// which go after cursor. Nil cursor means the first page.
+	GetPVZPagination(ctx context.Context, filter model.PVZFilter, after *model.PVZCursor, limit int) ([]model.PVZ, error)
+	CountPVZ(ctx context.Context, filter model.PVZFilter) (int, error)
 	GetPVZList(ctx context.Context) ([]model.PVZ, error)
+	GetPVZ(ctx context.Context, pvzID uuid.UUID) (model.PVZ, error)
 }
 
 type ReceptionRepository interface {
+	// CreateReception returns ErrPVZNotActive if PVZ is suspended or closed.
 	CreateReception(ctx context.Context, pvzID uuid.UUID) (model.Reception, error)
 	CloseLastReception(ctx context.Context, pvzID uuid.UUID) (model.Reception, error)
 }
 
 type ProductRepository interface {
-	AddProduct(ctx context.Context, pvzID uuid.UUID, productType string) (model.Product, error)
-	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
+	AddProduct(ctx context.Context, pvzID uuid.UUID, product model.NewProduct) (model.Product, error)
+	// AddProducts adds products in one transaction and returns
+	// indexes of products which types are not supported. If product type
+	// is deleted while products are added, ErrUnsupportedProductType is returned.
+	AddProducts(ctx context.Context, pvzID uuid.UUID, products []model.NewProduct) ([]model.Product, []int, error)
+	// DeleteLastProducts deletes count last added products of in progress reception
+	// and returns them, the last added first. Nothing is deleted if there are fewer products.
+	DeleteLastProducts(ctx context.Context, pvzID uuid.UUID, count int) ([]model.Product, error)
+}
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, email, passwordHash, role string) (model.User, error)
+	GetUserByEmail(ctx context.Context, email string) (model.User, error)
+}
+
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, tokenHash string, identity model.Identity, expiresAt time.Time) error
+	RotateRefreshToken(ctx context.Context, oldTokenHash, newTokenHash string, expiresAt time.Time) (model.Identity, error)
+	// RevokeTokens revokes access token of the identity and, if hash is not empty, refresh token
+	// of the same user in one transaction. Nothing is revoked if refresh token is not found.
+	RevokeTokens(ctx context.Context, identity model.Identity, refreshTokenHash string) error
+	IsAccessTokenRevoked(ctx context.Context, tokenID uuid.UUID) (bool, error)
+}
+
+type CityRepository interface {
+	GetCities(ctx context.Context) ([]model.City, error)
+	// GetCity looks city up by code or any of its names, case insensitive.
+	GetCity(ctx context.Context, key string) (model.City, error)
+	// CreateCity creates active city or activates deactivated one with the same name
+	// and code. Translations of activated city are replaced.
+	CreateCity(ctx context.Context, city model.City) (model.City, error)
+	DeactivateCity(ctx context.Context, cityID uuid.UUID) (model.City, error)
+	// DeleteCity returns ErrCityHasPVZs if there are PVZs in the city.
+	DeleteCity(ctx context.Context, cityID uuid.UUID) error
+}
+
+type ProductTypeRepository interface {
+	GetProductTypes(ctx context.Context) ([]model.ProductType, error)
+	// GetProductType looks product type up by code or any of its names, case insensitive.
+	// It returns ErrUnsupportedProductType if there is no such type.
+	GetProductType(ctx context.Context, key string) (model.ProductType, error)
+	CreateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error)
+	UpdateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error)
+	// DeleteProductType returns ErrProductTypeInUse if there are products of the type.
+	DeleteProductType(ctx context.Context, productTypeID uuid.UUID) error
 }
@@ -2,45 +2,275 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
 
 	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
 )
 
 var _ Auth = (*AuthService)(nil)
 
 type AuthService struct {
-	tokenString string
+	userRepo        repository.UserRepository
+	tokenRepo       repository.TokenRepository
+	keys            *keySet
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
 }
 
-var errWrongToken = errors.New("wrong token")
+type claims struct {
+	jwt.RegisteredClaims
+
+	Role string `json:"role"`
+}
 
-func newAuthService(cfg config.ServerConfig) (*AuthService, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+// Refresh tokens are random strings of this many bytes.
+const _refreshTokenSize = 32
+
+// bcrypt rejects longer passwords.
+const _maxPasswordSize = 72
+
+var errWrongToken = errors.New("wrong token")
 
-	tokenString, err := token.SignedString([]byte(cfg.ServerSecretKey))
+func newAuthService(
+	cfg config.ServerConfig,
+	userRepo repository.UserRepository,
+	tokenRepo repository.TokenRepository,
+) (*AuthService, error) {
+	keys, err := newKeySet(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("signing token: %w", err)
+		return nil, fmt.Errorf("creating key set: %w", err)
 	}
 
 	return &AuthService{
-		tokenString: tokenString,
+		userRepo:        userRepo,
+		tokenRepo:       tokenRepo,
+		keys:            keys,
+		accessTokenTTL:  cfg.ServerAccessTokenTTL,
+		refreshTokenTTL: cfg.ServerRefreshTokenTTL,
 	}, nil
 }
 
 // IssueToken implements Auth.
-func (a *AuthService) IssueToken(ctx context.Context) (string, error) {
-	return a.tokenString, nil
+func (a *AuthService) IssueToken(ctx context.Context, identity model.Identity) (string, error) {
+	if !isSupportedRole(identity.Role) {
+		return "", fmt.Errorf("issuing token: %w", ErrUnsupportedRole)
+	}
+
+	now := time.Now()
+	tokenString, err := a.keys.sign(claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   identity.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(a.accessTokenTTL)),
+		},
+		Role: identity.Role,
+	})
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+
+	return tokenString, nil
 }
 
 // VerifyToken implements Auth.
-func (a *AuthService) VerifyToken(ctx context.Context, tokenString string) error {
-	if tokenString != a.tokenString {
-		return errWrongToken
+func (a *AuthService) VerifyToken(ctx context.Context, tokenString string) (model.Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		&c,
+		a.keys.keyFunc,
+		jwt.WithValidMethods(a.keys.validMethods()),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("%w: %w", errWrongToken, err)
+	}
+
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil || !isSupportedRole(c.Role) {
+		return model.Identity{}, errWrongToken
+	}
+
+	tokenID, err := uuid.Parse(c.ID)
+	if err != nil {
+		return model.Identity{}, errWrongToken
+	}
+
+	revoked, err := a.tokenRepo.IsAccessTokenRevoked(ctx, tokenID)
+	if err != nil {
+		return model.Identity{}, fmt.Errorf("checking revocation: %w: %w", ErrCannotVerifyToken, err)
+	} else if revoked {
+		return model.Identity{}, errWrongToken
+	}
+
+	return model.Identity{
+		UserID:    userID,
+		Role:      c.Role,
+		TokenID:   tokenID,
+		ExpiresAt: c.ExpiresAt.Time,
+	}, nil
+}
+
+// PublicKeys implements Auth.
+func (a *AuthService) PublicKeys(ctx context.Context) []model.JWK {
+	return a.keys.publicKeys()
+}
+
+// Refresh implements Auth.
+func (a *AuthService) Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error) {
+	newRefreshToken, err := generateRefreshToken()
+	if err != nil {
+		return model.TokenPair{}, ErrCannotRefresh
+	}
+
+	identity, err := a.tokenRepo.RotateRefreshToken(
+		ctx,
+		hashRefreshToken(refreshToken),
+		hashRefreshToken(newRefreshToken),
+		time.Now().Add(a.refreshTokenTTL),
+	)
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return model.TokenPair{}, fmt.Errorf("refreshing token: %w", ErrInvalidRefreshToken)
+	} else if err != nil {
+		return model.TokenPair{}, ErrCannotRefresh
+	}
+
+	accessToken, err := a.IssueToken(ctx, identity)
+	if err != nil {
+		return model.TokenPair{}, ErrCannotRefresh
+	}
+
+	return model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// Revoke implements Auth.
+func (a *AuthService) Revoke(ctx context.Context, identity model.Identity, refreshToken string) error {
+	var refreshTokenHash string
+	if refreshToken != "" {
+		refreshTokenHash = hashRefreshToken(refreshToken)
+	}
+
+	err := a.tokenRepo.RevokeTokens(ctx, identity, refreshTokenHash)
+	if errors.Is(err, repository.ErrRefreshTokenNotFound) {
+		return fmt.Errorf("revoking token: %w", ErrInvalidRefreshToken)
+	} else if err != nil {
+		return ErrCannotRevoke
 	}
 
 	return nil
 }
+
+// Register implements Auth.
+func (a *AuthService) Register(ctx context.Context, email, password, role string) (model.User, error) {
+	if !isSupportedRole(role) {
+		return model.User{}, fmt.Errorf("registering user: %w", ErrUnsupportedRole)
+	}
+
+	// Anyone can register as employee, other users are registered by moderators.
+	if role != model.RoleEmployee {
+		identity, ok := IdentityFromContext(ctx)
+		if !ok || !identity.HasRole(model.RoleModerator) {
+			return model.User{}, fmt.Errorf("registering user: %w", ErrRoleNotAllowed)
+		}
+	}
+
+	if email == "" {
+		return model.User{}, fmt.Errorf("registering user: %w", ErrInvalidEmail)
+	}
+
+	if password == "" || len(password) > _maxPasswordSize {
+		return model.User{}, fmt.Errorf("registering user: %w", ErrInvalidPassword)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return model.User{}, ErrCannotRegister
+	}
+
+	user, err := a.userRepo.CreateUser(ctx, email, string(passwordHash), role)
+	if errors.Is(err, repository.ErrUserExists) {
+		return model.User{}, fmt.Errorf("registering user: %w", ErrUserExists)
+	} else if err != nil {
+		return model.User{}, ErrCannotRegister
+	}
+
+	return user, nil
+}
+
+// Login implements Auth.
+func (a *AuthService) Login(ctx context.Context, email, password string) (model.TokenPair, error) {
+	user, err := a.userRepo.GetUserByEmail(ctx, email)
+	if errors.Is(err, repository.ErrUserNotFound) {
+		return model.TokenPair{}, fmt.Errorf("logging in: %w", ErrInvalidCredentials)
+	} else if err != nil {
+		return model.TokenPair{}, ErrCannotLogin
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return model.TokenPair{}, fmt.Errorf("logging in: %w", ErrInvalidCredentials)
+	} else if err != nil {
+		return model.TokenPair{}, ErrCannotLogin
+	}
+
+	identity := model.Identity{
+		UserID: user.ID,
+		Role:   user.Role,
+	}
+
+	accessToken, err := a.IssueToken(ctx, identity)
+	if err != nil {
+		return model.TokenPair{}, ErrCannotLogin
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return model.TokenPair{}, ErrCannotLogin
+	}
+
+	err = a.tokenRepo.CreateRefreshToken(ctx, hashRefreshToken(refreshToken), identity, time.Now().Add(a.refreshTokenTTL))
+	if err != nil {
+		return model.TokenPair{}, ErrCannotLogin
+	}
+
+	return model.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func isSupportedRole(role string) bool {
+	return role == model.RoleEmployee || role == model.RoleModerator
+}
+
+func generateRefreshToken() (string, error) {
+	b := make([]byte, _refreshTokenSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("reading random bytes: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Only hashes of refresh tokens are stored, so a database leak doesn't leak tokens.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
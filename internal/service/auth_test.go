@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+func TestVerifyTokenRevocationCheckFailed(t *testing.T) {
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm: jwt.SigningMethodHS256.Alg(),
+		ServerSecretKey:        "secret",
+	})
+
+	tokenString, err := auth.IssueToken(context.Background(), model.Identity{UserID: uuid.New(), Role: model.RoleEmployee})
+	require.NoError(t, err, "Failed to issue token")
+
+	// Valid token must not be reported as wrong when database is down.
+	auth.tokenRepo = unavailableTokens{}
+	_, err = auth.VerifyToken(context.Background(), tokenString)
+	require.ErrorIs(t, err, ErrCannotVerifyToken)
+	require.NotErrorIs(t, err, errWrongToken)
+}
+
+func TestRegister(t *testing.T) {
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm: jwt.SigningMethodHS256.Alg(),
+		ServerSecretKey:        "secret",
+	})
+	auth.userRepo = createdUsers{}
+
+	moderatorCtx := WithIdentity(context.Background(), model.Identity{UserID: uuid.New(), Role: model.RoleModerator})
+	employeeCtx := WithIdentity(context.Background(), model.Identity{UserID: uuid.New(), Role: model.RoleEmployee})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		email    string
+		password string
+		role     string
+		err      error
+	}{
+		{"employee by anyone", context.Background(), "employee@example.com", "password", model.RoleEmployee, nil},
+		{"moderator by anyone", context.Background(), "anonymous@example.com", "password", model.RoleModerator, ErrRoleNotAllowed},
+		{"moderator by employee", employeeCtx, "employee@example.com", "password", model.RoleModerator, ErrRoleNotAllowed},
+		{"moderator by moderator", moderatorCtx, "moderator@example.com", "password", model.RoleModerator, nil},
+		{"unsupported role", moderatorCtx, "admin@example.com", "password", "admin", ErrUnsupportedRole},
+		{"empty email", context.Background(), "", "password", model.RoleEmployee, ErrInvalidEmail},
+		{"empty password", context.Background(), "empty@example.com", "", model.RoleEmployee, ErrInvalidPassword},
+		{"long password", context.Background(), "long@example.com", strings.Repeat("п", 37), model.RoleEmployee, ErrInvalidPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := auth.Register(tt.ctx, tt.email, tt.password, tt.role)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err, "Failed to register user")
+			require.Equal(t, tt.role, user.Role)
+		})
+	}
+}
+
+// createdUsers creates every user it is asked to.
+type createdUsers struct {
+	repository.UserRepository
+}
+
+func (createdUsers) CreateUser(_ context.Context, email, passwordHash, role string) (model.User, error) {
+	return model.User{ID: uuid.New(), Email: email, PasswordHash: passwordHash, Role: role}, nil
+}
+
+// unavailableTokens fails every revocation check.
+type unavailableTokens struct {
+	repository.TokenRepository
+}
+
+func (unavailableTokens) IsAccessTokenRevoked(_ context.Context, _ uuid.UUID) (bool, error) {
+	return false, errors.New("connection refused")
+}
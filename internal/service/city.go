@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+var _ City = (*CityService)(nil)
+
+type CityService struct {
+	repo repository.CityRepository
+}
+
+func newCityService(repo repository.CityRepository) *CityService {
+	return &CityService{
+		repo: repo,
+	}
+}
+
+// GetCities implements City.
+func (c *CityService) GetCities(ctx context.Context) ([]model.City, error) {
+	cities, err := c.repo.GetCities(ctx)
+	if err != nil {
+		return nil, ErrCannotGetCities
+	}
+
+	return cities, nil
+}
+
+// CreateCity implements City.
+func (c *CityService) CreateCity(ctx context.Context, city model.City) (model.City, error) {
+	var err error
+
+	city.Name = strings.TrimSpace(city.Name)
+	if city.Name == "" {
+		return model.City{}, fmt.Errorf("creating city: %w", ErrInvalidCityName)
+	}
+
+	city.Code, city.Names, err = validateLocalization(city.Code, city.Names)
+	if err != nil {
+		return model.City{}, fmt.Errorf("creating city: %w", err)
+	}
+
+	city, err = c.repo.CreateCity(ctx, city)
+	if errors.Is(err, repository.ErrCityExists) {
+		return model.City{}, fmt.Errorf("creating city: %w", ErrCityExists)
+	} else if err != nil {
+		return model.City{}, ErrCannotCreateCity
+	}
+
+	return city, nil
+}
+
+// DeleteCity implements City.
+func (c *CityService) DeleteCity(ctx context.Context, cityID uuid.UUID, hard bool) (model.City, error) {
+	if !hard {
+		city, err := c.repo.DeactivateCity(ctx, cityID)
+		if errors.Is(err, repository.ErrCityNotFound) {
+			return model.City{}, fmt.Errorf("deactivating city: %w", ErrCityNotFound)
+		} else if err != nil {
+			return model.City{}, ErrCannotDeleteCity
+		}
+
+		return city, nil
+	}
+
+	err := c.repo.DeleteCity(ctx, cityID)
+	if errors.Is(err, repository.ErrCityNotFound) {
+		return model.City{}, fmt.Errorf("deleting city: %w", ErrCityNotFound)
+	} else if errors.Is(err, repository.ErrCityHasPVZs) {
+		return model.City{}, fmt.Errorf("deleting city: %w", ErrCityHasPVZs)
+	} else if err != nil {
+		return model.City{}, ErrCannotDeleteCity
+	}
+
+	return model.City{}, nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+// Cursors are opaque for clients: it is base64 encoded JSON,
+// so its content can be changed without breaking them.
+
+func encodeCursor(cursor model.PVZCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("marshaling cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor returns nil if cursor is empty, which means the first page.
+func decodeCursor(cursor string) (*model.PVZCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("decoding cursor: %w", err)
+	}
+
+	var result model.PVZCursor
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("unmarshaling cursor: %w", err)
+	}
+
+	// Cursor is issued only for existing PVZ, so both fields are set.
+	if result.ID == uuid.Nil || result.RegistrationDate.IsZero() {
+		return nil, errors.New("cursor is incomplete")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := model.PVZCursor{
+		RegistrationDate: time.Date(2025, 4, 15, 10, 30, 0, 123456000, time.UTC),
+		ID:               uuid.New(),
+	}
+
+	encoded, err := encodeCursor(cursor)
+	require.NoError(t, err, "Failed to encode cursor")
+
+	decoded, err := decodeCursor(encoded)
+	require.NoError(t, err, "Failed to decode cursor")
+	require.NotNil(t, decoded)
+	require.True(t, cursor.RegistrationDate.Equal(decoded.RegistrationDate))
+	require.Equal(t, cursor.ID, decoded.ID)
+
+	decoded, err = decodeCursor("")
+	require.NoError(t, err)
+	require.Nil(t, decoded, "Empty cursor means the first page")
+}
+
+func TestInvalidCursor(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	cursors := map[string]string{
+		"not base64":     "!!!",
+		"not json":       encode("cursor"),
+		"wrong id":       encode(`{"registration_date":"2025-04-15T10:30:00Z","id":"1"}`),
+		"wrong date":     encode(`{"registration_date":"yesterday","id":"` + uuid.NewString() + `"}`),
+		"missing id":     encode(`{"registration_date":"2025-04-15T10:30:00Z"}`),
+		"missing date":   encode(`{"id":"` + uuid.NewString() + `"}`),
+		"empty document": encode("{}"),
+	}
+
+	// Valid cursor cut by client is rejected too.
+	valid, err := encodeCursor(model.PVZCursor{RegistrationDate: time.Now(), ID: uuid.New()})
+	require.NoError(t, err, "Failed to encode cursor")
+	cursors["truncated"] = valid[:len(valid)-5]
+
+	service := newPVZService(&pageRepository{}, localizer{}, 10)
+	for name, cursor := range cursors {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.GetPVZPagination(context.Background(), model.PVZFilter{}, cursor, 0, false)
+			require.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
+
+func TestNextCursor(t *testing.T) {
+	repo := &pageRepository{}
+	for i := range 3 {
+		repo.pvzs = append(repo.pvzs, model.PVZ{
+			ID:               uuid.New(),
+			RegistrationDate: time.Date(2025, 4, 15, 10, i, 0, 0, time.UTC),
+		})
+	}
+
+	service := newPVZService(repo, localizer{}, 10)
+
+	page, err := service.GetPVZPagination(context.Background(), model.PVZFilter{}, "", 2, false)
+	require.NoError(t, err, "Failed to get first page")
+	require.Len(t, page.Items, 2)
+	require.NotEmpty(t, page.NextCursor, "Next cursor must be set if there are more PVZs")
+
+	page, err = service.GetPVZPagination(context.Background(), model.PVZFilter{}, page.NextCursor, 2, false)
+	require.NoError(t, err, "Failed to get last page")
+	require.Equal(t, repo.pvzs[2:], page.Items)
+	require.Empty(t, page.NextCursor, "Next cursor must not be set on the last page")
+}
+
+// pageRepository pages PVZs kept in (registration_date, id) order.
+type pageRepository struct {
+	repository.PVZRepository
+
+	pvzs []model.PVZ
+}
+
+func (r *pageRepository) GetPVZPagination(_ context.Context, _ model.PVZFilter, after *model.PVZCursor, limit int) ([]model.PVZ, error) {
+	start := 0
+	if after != nil {
+		for start < len(r.pvzs) && r.pvzs[start].ID != after.ID {
+			start++
+		}
+		start++
+	}
+
+	end := min(start+limit, len(r.pvzs))
+	if start > end {
+		return nil, nil
+	}
+
+	return r.pvzs[start:end], nil
+}
@@ -3,17 +3,68 @@ package service
 import "errors"
 
 var (
-	ErrCannotCreatePVZ = errors.New("cannot create pvz")
-	ErrCannotGetPVZ    = errors.New("cannot get pvz")
-	ErrUnsupportedCity = errors.New("city is not supported")
+	ErrCannotLogin         = errors.New("cannot log in")
+	ErrCannotRefresh       = errors.New("cannot refresh token")
+	ErrCannotRegister      = errors.New("cannot register user")
+	ErrCannotRevoke        = errors.New("cannot revoke token")
+	ErrCannotVerifyToken   = errors.New("cannot verify token")
+	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrInvalidEmail        = errors.New("email must not be empty")
+	ErrInvalidPassword     = errors.New("password must be from 1 to 72 bytes long")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid")
+	ErrRoleNotAllowed      = errors.New("only moderators can register users with this role")
+	ErrUnsupportedRole     = errors.New("role is not supported")
+	ErrUserExists          = errors.New("user already exists")
 
-	ErrCannotCloseReception  = errors.New("cannot close reception")
-	ErrCannotCreateReception = errors.New("cannot create reception")
-	ErrNoReceptionInProgress = errors.New("no reception is in progress")
-	ErrReceptionInProgress   = errors.New("last reception is in progress")
+	ErrCannotCreatePVZ      = errors.New("cannot create pvz")
+	ErrCannotGetPVZ         = errors.New("cannot get pvz")
+	ErrCannotUpdatePVZ      = errors.New("cannot update pvz")
+	ErrInvalidCapacity      = errors.New("capacity must not be negative")
+	ErrInvalidCursor        = errors.New("cursor is invalid")
+	ErrInvalidLocation      = errors.New("latitude must be within [-90, 90] and longitude within [-180, 180]")
+	ErrInvalidPageSize      = errors.New("page size is out of range")
+	ErrInvalidWorkingHours  = errors.New("working hours must be non-overlapping HH:MM intervals within weekdays 1-7")
+	ErrPVZNotFound          = errors.New("pvz was not found")
+	ErrUnsupportedCity      = errors.New("city is not supported")
+	ErrUnsupportedPVZStatus = errors.New("pvz status is not supported")
+
+	ErrCannotCloseReception       = errors.New("cannot close reception")
+	ErrCannotCreateReception      = errors.New("cannot create reception")
+	ErrNoReceptionInProgress      = errors.New("no reception is in progress")
+	ErrPVZNotActive               = errors.New("pvz is suspended or closed")
+	ErrReceptionInProgress        = errors.New("last reception is in progress")
+	ErrUnsupportedReceptionStatus = errors.New("reception status is not supported")
 
 	ErrCannotAddProduct       = errors.New("cannot add product")
 	ErrCannotDeleteProduct    = errors.New("cannot delete product")
+	ErrInvalidProductCount    = errors.New("product count must be positive")
+	ErrNotEnoughProducts      = errors.New("reception has fewer products than requested")
 	ErrUnsupportedProductType = errors.New("product type is not supported")
 	ErrReceptionIsEmpty       = errors.New("reception is empty")
+	ErrSerialNumberRequired   = errors.New("serial number is required for the product type")
+	ErrWeightRequired         = errors.New("weight is required for the product type")
+	ErrInvalidWeight          = errors.New("weight must be positive")
+	ErrProductTooHeavy        = errors.New("product is heavier than allowed for the product type")
+
+	ErrCannotCreateProductType = errors.New("cannot create product type")
+	ErrCannotDeleteProductType = errors.New("cannot delete product type")
+	ErrCannotGetProductTypes   = errors.New("cannot get product types")
+	ErrCannotUpdateProductType = errors.New("cannot update product type")
+	ErrInvalidMaxWeight        = errors.New("max weight must not be negative")
+	ErrInvalidProductTypeName  = errors.New("product type name must not be empty")
+	ErrProductTypeExists       = errors.New("product type already exists")
+	ErrProductTypeInUse        = errors.New("product type has products and cannot be deleted")
+	ErrProductTypeNotFound     = errors.New("product type was not found")
+
+	ErrCannotCreateCity = errors.New("cannot create city")
+	ErrCannotDeleteCity = errors.New("cannot delete city")
+	ErrCannotGetCities  = errors.New("cannot get cities")
+	ErrCityExists       = errors.New("city already exists")
+	ErrCityHasPVZs      = errors.New("city has pvzs and can only be deactivated")
+	ErrCityNotFound     = errors.New("city was not found")
+	ErrInvalidCityName  = errors.New("city name must not be empty")
+
+	ErrInvalidCode        = errors.New("code must consist of lowercase latin letters, digits, '-' and '_'")
+	ErrInvalidLanguage    = errors.New("translations must be keyed by language codes")
+	ErrInvalidTranslation = errors.New("translation must not be empty")
 )
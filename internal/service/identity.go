@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying identity.
+func WithIdentity(ctx context.Context, identity model.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns identity stored by WithIdentity.
+func IdentityFromContext(ctx context.Context) (model.Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(model.Identity)
+	return identity, ok
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+const _kidHeader = "kid"
+
+type signingKey struct {
+	id         string
+	method     jwt.SigningMethod
+	signKey    any
+	verifyKey  any
+	isInternal bool // Symmetric keys must never be published.
+}
+
+// keySet holds the current signing key and previous keys,
+// which are still accepted to verify tokens.
+type keySet struct {
+	current signingKey
+	keys    map[string]signingKey
+}
+
+var errUnknownKey = errors.New("unknown signing key")
+
+func newKeySet(cfg config.ServerConfig) (*keySet, error) {
+	current, err := loadCurrentKey(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("loading current key: %w", err)
+	}
+
+	ks := &keySet{
+		current: current,
+		keys: map[string]signingKey{
+			current.id: current,
+		},
+	}
+
+	for id, secret := range cfg.ServerPreviousSecretKeys {
+		if err := ks.add(signingKey{
+			id:         id,
+			method:     jwt.SigningMethodHS256,
+			verifyKey:  []byte(secret),
+			isInternal: true,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	for id, path := range cfg.ServerPreviousPublicKeyFiles {
+		key, err := loadPublicKey(id, path)
+		if err != nil {
+			return nil, fmt.Errorf("loading previous key %q: %w", id, err)
+		}
+
+		if err := ks.add(key); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+func (ks *keySet) add(key signingKey) error {
+	if _, ok := ks.keys[key.id]; ok {
+		return fmt.Errorf("duplicate key id %q", key.id)
+	}
+
+	ks.keys[key.id] = key
+
+	return nil
+}
+
+// sign signs token with the current key.
+func (ks *keySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.current.method, claims)
+	token.Header[_kidHeader] = ks.current.id
+
+	return token.SignedString(ks.current.signKey)
+}
+
+// keyFunc implements jwt.Keyfunc.
+func (ks *keySet) keyFunc(token *jwt.Token) (any, error) {
+	// Tokens without kid are verified with the current key.
+	key := ks.current
+	if kid, ok := token.Header[_kidHeader].(string); ok {
+		key, ok = ks.keys[kid]
+		if !ok {
+			return nil, errUnknownKey
+		}
+	}
+
+	// Prevents algorithm confusion between keys.
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+
+	return key.verifyKey, nil
+}
+
+func (ks *keySet) validMethods() []string {
+	methods := make([]string, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		methods = append(methods, key.method.Alg())
+	}
+
+	return methods
+}
+
+// publicKeys returns JWKs of all asymmetric keys.
+func (ks *keySet) publicKeys() []model.JWK {
+	jwks := make([]model.JWK, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		if key.isInternal {
+			continue
+		}
+
+		jwk := model.JWK{
+			Kid: key.id,
+			Use: "sig",
+			Alg: key.method.Alg(),
+		}
+
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+
+		jwks = append(jwks, jwk)
+	}
+
+	// Stable order is friendlier to caches.
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].Kid < jwks[j].Kid })
+
+	return jwks
+}
+
+func loadCurrentKey(cfg config.ServerConfig) (signingKey, error) {
+	switch cfg.ServerSigningAlgorithm {
+	case jwt.SigningMethodHS256.Alg():
+		if cfg.ServerSecretKey == "" {
+			return signingKey{}, errors.New("empty secret key")
+		}
+
+		return signingKey{
+			id:         cfg.ServerSigningKeyID,
+			method:     jwt.SigningMethodHS256,
+			signKey:    []byte(cfg.ServerSecretKey),
+			verifyKey:  []byte(cfg.ServerSecretKey),
+			isInternal: true,
+		}, nil
+	case jwt.SigningMethodRS256.Alg():
+		pem, err := os.ReadFile(cfg.ServerPrivateKeyFile)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("reading private key: %w", err)
+		}
+
+		private, err := jwt.ParseRSAPrivateKeyFromPEM(pem)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("parsing private key: %w", err)
+		}
+
+		return signingKey{
+			id:        cfg.ServerSigningKeyID,
+			method:    jwt.SigningMethodRS256,
+			signKey:   private,
+			verifyKey: &private.PublicKey,
+		}, nil
+	case jwt.SigningMethodEdDSA.Alg():
+		pem, err := os.ReadFile(cfg.ServerPrivateKeyFile)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("reading private key: %w", err)
+		}
+
+		private, err := jwt.ParseEdPrivateKeyFromPEM(pem)
+		if err != nil {
+			return signingKey{}, fmt.Errorf("parsing private key: %w", err)
+		}
+
+		return signingKey{
+			id:        cfg.ServerSigningKeyID,
+			method:    jwt.SigningMethodEdDSA,
+			signKey:   private,
+			verifyKey: private.(crypto.Signer).Public(),
+		}, nil
+	default:
+		return signingKey{}, fmt.Errorf("unsupported signing algorithm %q", cfg.ServerSigningAlgorithm)
+	}
+}
+
+func loadPublicKey(id, path string) (signingKey, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("reading public key: %w", err)
+	}
+
+	if public, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+		return signingKey{
+			id:        id,
+			method:    jwt.SigningMethodRS256,
+			verifyKey: public,
+		}, nil
+	}
+
+	public, err := jwt.ParseEdPublicKeyFromPEM(pem)
+	if err != nil {
+		return signingKey{}, errors.New("public key is neither RSA nor Ed25519")
+	}
+
+	return signingKey{
+		id:        id,
+		method:    jwt.SigningMethodEdDSA,
+		verifyKey: public,
+	}, nil
+}
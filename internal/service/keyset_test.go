@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+const _testRSABits = 2048
+
+func TestHS256SignedWithPublicKeyRejected(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, _testRSABits)
+	require.NoError(t, err, "Failed to generate key")
+
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm: jwt.SigningMethodRS256.Alg(),
+		ServerSigningKeyID:     "current",
+		ServerPrivateKeyFile:   writePrivateKey(t, private),
+	})
+
+	// Public key is known to anyone, so it must not work as HMAC secret.
+	publicPEM, err := os.ReadFile(writePublicKey(t, &private.PublicKey))
+	require.NoError(t, err, "Failed to read public key")
+
+	for _, kid := range []string{"current", ""} {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+		if kid != "" {
+			token.Header[_kidHeader] = kid
+		}
+
+		tokenString, err := token.SignedString(publicPEM)
+		require.NoError(t, err, "Failed to sign token")
+
+		_, err = auth.VerifyToken(context.Background(), tokenString)
+		require.ErrorIs(t, err, errWrongToken, "Token with kid %q must be rejected", kid)
+	}
+}
+
+func TestUnknownKeyIDRejected(t *testing.T) {
+	secret := "secret"
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm: jwt.SigningMethodHS256.Alg(),
+		ServerSigningKeyID:     "current",
+		ServerSecretKey:        secret,
+	})
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	token.Header[_kidHeader] = "unknown"
+	tokenString, err := token.SignedString([]byte(secret))
+	require.NoError(t, err, "Failed to sign token")
+
+	_, err = auth.VerifyToken(context.Background(), tokenString)
+	require.ErrorIs(t, err, errWrongToken)
+	require.ErrorIs(t, err, errUnknownKey)
+}
+
+func TestTokenSignedWithPreviousKeyVerified(t *testing.T) {
+	_, previousEd, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "Failed to generate key")
+
+	currentRSA, err := rsa.GenerateKey(rand.Reader, _testRSABits)
+	require.NoError(t, err, "Failed to generate key")
+
+	// Tokens issued before keys were rotated.
+	identity := model.Identity{UserID: uuid.New(), Role: model.RoleEmployee}
+	previousAuths := []*AuthService{
+		newTestAuth(t, config.ServerConfig{
+			ServerSigningAlgorithm: jwt.SigningMethodHS256.Alg(),
+			ServerSigningKeyID:     "previous-hs",
+			ServerSecretKey:        "previous secret",
+		}),
+		newTestAuth(t, config.ServerConfig{
+			ServerSigningAlgorithm: jwt.SigningMethodEdDSA.Alg(),
+			ServerSigningKeyID:     "previous-ed",
+			ServerPrivateKeyFile:   writePrivateKey(t, previousEd),
+		}),
+	}
+
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm:   jwt.SigningMethodRS256.Alg(),
+		ServerSigningKeyID:       "current",
+		ServerPrivateKeyFile:     writePrivateKey(t, currentRSA),
+		ServerPreviousSecretKeys: map[string]string{"previous-hs": "previous secret"},
+		ServerPreviousPublicKeyFiles: map[string]string{
+			"previous-ed": writePublicKey(t, previousEd.Public()),
+		},
+	})
+
+	for _, previous := range append(previousAuths, auth) {
+		tokenString, err := previous.IssueToken(context.Background(), identity)
+		require.NoError(t, err, "Failed to issue token")
+
+		verified, err := auth.VerifyToken(context.Background(), tokenString)
+		require.NoError(t, err, "Token must be verified")
+		require.Equal(t, identity.UserID, verified.UserID)
+		require.Equal(t, identity.Role, verified.Role)
+	}
+}
+
+func TestPublicKeysArePublic(t *testing.T) {
+	edPublic, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, "Failed to generate key")
+
+	private, err := rsa.GenerateKey(rand.Reader, _testRSABits)
+	require.NoError(t, err, "Failed to generate key")
+
+	auth := newTestAuth(t, config.ServerConfig{
+		ServerSigningAlgorithm:       jwt.SigningMethodRS256.Alg(),
+		ServerSigningKeyID:           "current",
+		ServerPrivateKeyFile:         writePrivateKey(t, private),
+		ServerPreviousSecretKeys:     map[string]string{"previous-hs": "previous secret"},
+		ServerPreviousPublicKeyFiles: map[string]string{"previous-ed": writePublicKey(t, edPublic)},
+	})
+
+	// Secret keys are not published at all, others are published without private parts.
+	jwks := auth.PublicKeys(context.Background())
+	require.Equal(t, []model.JWK{
+		{
+			Kty: "RSA",
+			Kid: "current",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(private.N.Bytes()),
+			E:   "AQAB",
+		},
+		{
+			Kty: "OKP",
+			Kid: "previous-ed",
+			Use: "sig",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(edPublic),
+		},
+	}, jwks)
+}
+
+// noRevokedTokens reports every access token as not revoked.
+type noRevokedTokens struct {
+	repository.TokenRepository
+}
+
+func (noRevokedTokens) IsAccessTokenRevoked(_ context.Context, _ uuid.UUID) (bool, error) {
+	return false, nil
+}
+
+func newTestAuth(t *testing.T, cfg config.ServerConfig) *AuthService {
+	t.Helper()
+
+	cfg.ServerAccessTokenTTL = time.Hour
+	auth, err := newAuthService(cfg, nil, noRevokedTokens{})
+	require.NoError(t, err, "Failed to create auth service")
+
+	return auth
+}
+
+func testClaims() claims {
+	now := time.Now()
+
+	return claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Role: model.RoleModerator,
+	}
+}
+
+func writePrivateKey(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err, "Failed to marshal private key")
+
+	return writePEM(t, "PRIVATE KEY", der)
+}
+
+func writePublicKey(t *testing.T, key any) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err, "Failed to marshal public key")
+
+	return writePEM(t, "PUBLIC KEY", der)
+}
+
+func writePEM(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600)
+	require.NoError(t, err, "Failed to write key")
+
+	return path
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+)
+
+var _codePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+type languagesKey struct{}
+
+// WithLanguages returns a copy of ctx carrying languages preferred by client.
+func WithLanguages(ctx context.Context, languages []string) context.Context {
+	return context.WithValue(ctx, languagesKey{}, languages)
+}
+
+// LanguagesFromContext returns languages stored by WithLanguages, the most preferred first.
+func LanguagesFromContext(ctx context.Context) []string {
+	languages, _ := ctx.Value(languagesKey{}).([]string)
+	return languages
+}
+
+// ParseAcceptLanguage returns base languages from Accept-Language header
+// ordered by preference. Invalid header means no preference.
+func ParseAcceptLanguage(header string) []string {
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil {
+		return nil
+	}
+
+	languages := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		base, _ := tag.Base()
+		if !slices.Contains(languages, base.String()) {
+			languages = append(languages, base.String())
+		}
+	}
+
+	return languages
+}
+
+// normalizeLanguage returns base language of valid language tag.
+func normalizeLanguage(tag string) (string, bool) {
+	base, err := language.ParseBase(tag)
+	if err != nil {
+		return "", false
+	}
+
+	return base.String(), true
+}
+
+// validateLocalization normalizes code and translations of city or product type.
+// Codes are lowercase latin letters, digits, '-' and '_', translations are keyed by languages.
+func validateLocalization(code string, names map[string]string) (string, map[string]string, error) {
+	code = strings.TrimSpace(code)
+	if !_codePattern.MatchString(code) {
+		return "", nil, ErrInvalidCode
+	}
+
+	normalized := make(map[string]string, len(names))
+	for tag, name := range names {
+		language, ok := normalizeLanguage(tag)
+		if !ok {
+			return "", nil, ErrInvalidLanguage
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return "", nil, ErrInvalidTranslation
+		}
+
+		// Default name is not a translation.
+		if language != model.DefaultLanguage {
+			normalized[language] = name
+		}
+	}
+
+	return code, normalized, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+// localizer translates default names of cities and product types
+// returned by repository to languages preferred by client.
+// Lookups are served by reference data cache of repository.
+type localizer struct {
+	cities       repository.CityRepository
+	productTypes repository.ProductTypeRepository
+}
+
+func (l localizer) pvz(ctx context.Context, pvz model.PVZ) model.PVZ {
+	languages := LanguagesFromContext(ctx)
+	if len(languages) == 0 {
+		return pvz
+	}
+
+	// City can't be missing, but untranslated name is better than error.
+	if city, err := l.cities.GetCity(ctx, pvz.City); err == nil {
+		pvz.City = city.LocalizedName(languages...)
+	}
+
+	for i := range pvz.Receptions {
+		pvz.Receptions[i].Products = l.products(ctx, pvz.Receptions[i].Products)
+	}
+
+	return pvz
+}
+
+func (l localizer) pvzs(ctx context.Context, pvzs []model.PVZ) []model.PVZ {
+	for i := range pvzs {
+		pvzs[i] = l.pvz(ctx, pvzs[i])
+	}
+
+	return pvzs
+}
+
+func (l localizer) product(ctx context.Context, product model.Product) model.Product {
+	languages := LanguagesFromContext(ctx)
+	if len(languages) == 0 {
+		return product
+	}
+
+	if productType, err := l.productTypes.GetProductType(ctx, product.Type); err == nil {
+		product.Type = productType.LocalizedName(languages...)
+	}
+
+	return product
+}
+
+func (l localizer) products(ctx context.Context, products []model.Product) []model.Product {
+	for i := range products {
+		products[i] = l.product(ctx, products[i])
+	}
+
+	return products
+}
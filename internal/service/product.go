@@ -1,9 +1,13 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -14,34 +18,198 @@ import (
 var _ Product = (*ProductService)(nil)
 
 type ProductService struct {
-	repo repository.ProductRepository
+	repo      repository.ProductRepository
+	types     repository.ProductTypeRepository
+	localizer localizer
+	events    publisher
 }
 
-func newProductService(repo repository.ProductRepository) *ProductService {
+func newProductService(repo repository.ProductRepository, types repository.ProductTypeRepository, localizer localizer, events publisher) *ProductService {
 	return &ProductService{
-		repo: repo,
+		repo:      repo,
+		types:     types,
+		localizer: localizer,
+		events:    events,
 	}
 }
 
 // AddProduct implements Product.
-func (p *ProductService) AddProduct(ctx context.Context, pvzID uuid.UUID, productType string) (model.Product, error) {
-	product, err := p.repo.AddProduct(ctx, pvzID, productType)
+func (p *ProductService) AddProduct(ctx context.Context, pvzID uuid.UUID, newProduct model.NewProduct) (model.Product, error) {
+	productType, err := p.types.GetProductType(ctx, newProduct.Type)
 	if errors.Is(err, repository.ErrUnsupportedProductType) {
 		return model.Product{}, fmt.Errorf("adding product: %w", ErrUnsupportedProductType)
 	} else if err != nil {
 		return model.Product{}, ErrCannotAddProduct
 	}
 
-	return product, nil
+	if err := checkProductAttributes(productType, newProduct); err != nil {
+		return model.Product{}, fmt.Errorf("adding product: %w", err)
+	}
+
+	// Type may be given by code or any name, repository gets default name.
+	newProduct.Type = productType.Name
+
+	product, err := p.repo.AddProduct(ctx, pvzID, newProduct)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return model.Product{}, fmt.Errorf("adding product: %w", ErrPVZNotFound)
+	} else if errors.Is(err, repository.ErrNoReceptionInProgress) {
+		return model.Product{}, fmt.Errorf("adding product: %w", ErrNoReceptionInProgress)
+	} else if errors.Is(err, repository.ErrUnsupportedProductType) {
+		return model.Product{}, fmt.Errorf("adding product: %w", ErrUnsupportedProductType)
+	} else if err != nil {
+		return model.Product{}, ErrCannotAddProduct
+	}
+
+	p.events.Publish(model.Event{
+		Type:       model.EventProductAdded,
+		PVZID:      pvzID,
+		Product:    product,
+		OccurredAt: time.Now(),
+	})
+
+	return p.localizer.product(ctx, product), nil
+}
+
+// AddProducts implements Product.
+func (p *ProductService) AddProducts(ctx context.Context, pvzID uuid.UUID, newProducts []model.NewProduct) ([]model.Product, []model.ProductRejection, error) {
+	// Types may be given by code or any name. Each distinct type is looked up
+	// once, types missing from cache are looked up in database like in AddProduct.
+	// Unsupported types are kept as zero values, so they are not looked up again.
+	productTypes := make(map[string]model.ProductType)
+	for _, newProduct := range newProducts {
+		key := model.LookupKey(newProduct.Type)
+		if _, ok := productTypes[key]; ok {
+			continue
+		}
+
+		productType, err := p.types.GetProductType(ctx, newProduct.Type)
+		if err != nil && !errors.Is(err, repository.ErrUnsupportedProductType) {
+			return nil, nil, ErrCannotAddProduct
+		}
+		productTypes[key] = productType
+	}
+
+	// Products violating attributes of their types are rejected before
+	// reaching repository, indexes maps accepted products to positions in request.
+	var (
+		accepted   = make([]model.NewProduct, 0, len(newProducts))
+		indexes    = make([]int, 0, len(newProducts))
+		rejections []model.ProductRejection
+	)
+	for i, newProduct := range newProducts {
+		productType := productTypes[model.LookupKey(newProduct.Type)]
+
+		err := ErrUnsupportedProductType
+		if productType.ID != uuid.Nil {
+			err = checkProductAttributes(productType, newProduct)
+		}
+
+		if err != nil {
+			rejections = append(rejections, model.ProductRejection{
+				Index:  i,
+				Type:   newProduct.Type,
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		// Repository gets default name.
+		newProduct.Type = productType.Name
+		accepted = append(accepted, newProduct)
+		indexes = append(indexes, i)
+	}
+
+	products, rejected, err := p.repo.AddProducts(ctx, pvzID, accepted)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return nil, nil, fmt.Errorf("adding products: %w", ErrPVZNotFound)
+	} else if errors.Is(err, repository.ErrNoReceptionInProgress) {
+		return nil, nil, fmt.Errorf("adding products: %w", ErrNoReceptionInProgress)
+	} else if errors.Is(err, repository.ErrUnsupportedProductType) {
+		return nil, nil, fmt.Errorf("adding products: %w", ErrUnsupportedProductType)
+	} else if err != nil {
+		return nil, nil, ErrCannotAddProduct
+	}
+
+	// Product type could be deleted after it was checked.
+	for _, i := range rejected {
+		rejections = append(rejections, model.ProductRejection{
+			Index:  indexes[i],
+			Type:   newProducts[indexes[i]].Type,
+			Reason: ErrUnsupportedProductType.Error(),
+		})
+	}
+
+	slices.SortFunc(rejections, func(a, b model.ProductRejection) int {
+		return cmp.Compare(a.Index, b.Index)
+	})
+
+	now := time.Now()
+	for _, product := range products {
+		p.events.Publish(model.Event{
+			Type:       model.EventProductAdded,
+			PVZID:      pvzID,
+			Product:    product,
+			OccurredAt: now,
+		})
+	}
+
+	return p.localizer.products(ctx, products), rejections, nil
 }
 
 // DeleteLastProduct implements Product.
 func (p *ProductService) DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error {
-	err := p.repo.DeleteLastProduct(ctx, pvzID)
-	if errors.Is(err, repository.ErrReceptionIsEmpty) {
-		return fmt.Errorf("deleting product: %w", ErrReceptionIsEmpty)
+	_, err := p.DeleteLastProducts(ctx, pvzID, 1)
+	return err
+}
+
+// DeleteLastProducts implements Product.
+func (p *ProductService) DeleteLastProducts(ctx context.Context, pvzID uuid.UUID, count int) ([]model.Product, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("deleting products: %w", ErrInvalidProductCount)
+	}
+
+	products, err := p.repo.DeleteLastProducts(ctx, pvzID, count)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return nil, fmt.Errorf("deleting products: %w", ErrPVZNotFound)
+	} else if errors.Is(err, repository.ErrNoReceptionInProgress) {
+		return nil, fmt.Errorf("deleting products: %w", ErrNoReceptionInProgress)
+	} else if errors.Is(err, repository.ErrReceptionIsEmpty) {
+		return nil, fmt.Errorf("deleting products: %w", ErrReceptionIsEmpty)
+	} else if errors.Is(err, repository.ErrNotEnoughProducts) {
+		return nil, fmt.Errorf("deleting products: %w", ErrNotEnoughProducts)
 	} else if err != nil {
-		return ErrCannotDeleteProduct
+		return nil, ErrCannotDeleteProduct
+	}
+
+	now := time.Now()
+	for _, product := range products {
+		p.events.Publish(model.Event{
+			Type:       model.EventProductRemoved,
+			PVZID:      pvzID,
+			Product:    product,
+			OccurredAt: now,
+		})
+	}
+
+	return p.localizer.products(ctx, products), nil
+}
+
+// checkProductAttributes checks that product satisfies attributes of its type.
+func checkProductAttributes(productType model.ProductType, product model.NewProduct) error {
+	if product.Weight < 0 {
+		return ErrInvalidWeight
+	}
+
+	if productType.SerialNumberRequired && strings.TrimSpace(product.SerialNumber) == "" {
+		return ErrSerialNumberRequired
+	}
+
+	if productType.MaxWeight > 0 {
+		if product.Weight == 0 {
+			return ErrWeightRequired
+		} else if product.Weight > productType.MaxWeight {
+			return ErrProductTooHeavy
+		}
 	}
 
 	return nil
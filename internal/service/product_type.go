@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/sudeeya/avito-assignment/internal/model"
+	"github.com/sudeeya/avito-assignment/internal/repository"
+)
+
+var _ ProductType = (*ProductTypeService)(nil)
+
+type ProductTypeService struct {
+	repo repository.ProductTypeRepository
+}
+
+func newProductTypeService(repo repository.ProductTypeRepository) *ProductTypeService {
+	return &ProductTypeService{
+		repo: repo,
+	}
+}
+
+// GetProductTypes implements ProductType.
+func (p *ProductTypeService) GetProductTypes(ctx context.Context) ([]model.ProductType, error) {
+	productTypes, err := p.repo.GetProductTypes(ctx)
+	if err != nil {
+		return nil, ErrCannotGetProductTypes
+	}
+
+	return productTypes, nil
+}
+
+// CreateProductType implements ProductType.
+func (p *ProductTypeService) CreateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error) {
+	productType, err := validateProductType(productType)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("creating product type: %w", err)
+	}
+
+	created, err := p.repo.CreateProductType(ctx, productType)
+	if errors.Is(err, repository.ErrProductTypeExists) {
+		return model.ProductType{}, fmt.Errorf("creating product type: %w", ErrProductTypeExists)
+	} else if err != nil {
+		return model.ProductType{}, ErrCannotCreateProductType
+	}
+
+	return created, nil
+}
+
+// UpdateProductType implements ProductType.
+func (p *ProductTypeService) UpdateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error) {
+	productType, err := validateProductType(productType)
+	if err != nil {
+		return model.ProductType{}, fmt.Errorf("updating product type: %w", err)
+	}
+
+	updated, err := p.repo.UpdateProductType(ctx, productType)
+	if errors.Is(err, repository.ErrProductTypeNotFound) {
+		return model.ProductType{}, fmt.Errorf("updating product type: %w", ErrProductTypeNotFound)
+	} else if errors.Is(err, repository.ErrProductTypeExists) {
+		return model.ProductType{}, fmt.Errorf("updating product type: %w", ErrProductTypeExists)
+	} else if err != nil {
+		return model.ProductType{}, ErrCannotUpdateProductType
+	}
+
+	return updated, nil
+}
+
+// DeleteProductType implements ProductType.
+func (p *ProductTypeService) DeleteProductType(ctx context.Context, productTypeID uuid.UUID) error {
+	err := p.repo.DeleteProductType(ctx, productTypeID)
+	if errors.Is(err, repository.ErrProductTypeNotFound) {
+		return fmt.Errorf("deleting product type: %w", ErrProductTypeNotFound)
+	} else if errors.Is(err, repository.ErrProductTypeInUse) {
+		return fmt.Errorf("deleting product type: %w", ErrProductTypeInUse)
+	} else if err != nil {
+		return ErrCannotDeleteProductType
+	}
+
+	return nil
+}
+
+// validateProductType returns product type with normalized name, code and translations.
+func validateProductType(productType model.ProductType) (model.ProductType, error) {
+	productType.Name = strings.TrimSpace(productType.Name)
+	if productType.Name == "" {
+		return model.ProductType{}, ErrInvalidProductTypeName
+	}
+
+	if productType.MaxWeight < 0 {
+		return model.ProductType{}, ErrInvalidMaxWeight
+	}
+
+	var err error
+	productType.Code, productType.Names, err = validateLocalization(productType.Code, productType.Names)
+	if err != nil {
+		return model.ProductType{}, err
+	}
+
+	return productType, nil
+}
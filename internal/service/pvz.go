@@ -1,11 +1,16 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+
 	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/repository"
 )
@@ -13,35 +18,118 @@ import (
 var _ PVZ = (*PVZService)(nil)
 
 type PVZService struct {
-	repo repository.PVZRepository
+	repo        repository.PVZRepository
+	localizer   localizer
+	maxPageSize int
 }
 
-func newPVZService(repo repository.PVZRepository) *PVZService {
+func newPVZService(repo repository.PVZRepository, localizer localizer, maxPageSize int) *PVZService {
 	return &PVZService{
-		repo: repo,
+		repo:        repo,
+		localizer:   localizer,
+		maxPageSize: maxPageSize,
 	}
 }
 
 // CreatePVZ implements PVZ.
-func (p *PVZService) CreatePVZ(ctx context.Context, city string) (model.PVZ, error) {
-	pvz, err := p.repo.CreatePVZ(ctx, city)
+func (p *PVZService) CreatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error) {
+	if pvz.Status == "" {
+		pvz.Status = model.PVZStatusActive
+	}
+
+	pvz, err := validatePVZ(pvz)
+	if err != nil {
+		return model.PVZ{}, fmt.Errorf("creating pvz: %w", err)
+	}
+
+	pvz, err = p.repo.CreatePVZ(ctx, pvz)
 	if errors.Is(err, repository.ErrUnsupportedCity) {
 		return model.PVZ{}, fmt.Errorf("creating pvz: %w", ErrUnsupportedCity)
 	} else if err != nil {
 		return model.PVZ{}, ErrCannotCreatePVZ
 	}
 
-	return pvz, nil
+	return p.localizer.pvz(ctx, pvz), nil
+}
+
+// UpdatePVZ implements PVZ.
+func (p *PVZService) UpdatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error) {
+	pvz, err := validatePVZ(pvz)
+	if err != nil {
+		return model.PVZ{}, fmt.Errorf("updating pvz: %w", err)
+	}
+
+	pvz, err = p.repo.UpdatePVZ(ctx, pvz)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return model.PVZ{}, fmt.Errorf("updating pvz: %w", ErrPVZNotFound)
+	} else if err != nil {
+		return model.PVZ{}, ErrCannotUpdatePVZ
+	}
+
+	return p.localizer.pvz(ctx, pvz), nil
 }
 
 // GetPVZPagination implements PVZ.
-func (p *PVZService) GetPVZPagination(ctx context.Context, start time.Time, end time.Time, limit int, offset int) ([]model.PVZ, error) {
-	pvzs, err := p.repo.GetPVZPagination(ctx, start, end, limit, offset)
+func (p *PVZService) GetPVZPagination(ctx context.Context, filter model.PVZFilter, cursor string, limit int, withTotal bool) (model.PVZPage, error) {
+	if limit == 0 {
+		limit = p.maxPageSize
+	} else if limit < 0 || limit > p.maxPageSize {
+		return model.PVZPage{}, fmt.Errorf("getting pvzs: %w", ErrInvalidPageSize)
+	}
+
+	if filter.Status != "" && filter.Status != model.ReceptionStatusInProgress && filter.Status != model.ReceptionStatusClose {
+		return model.PVZPage{}, fmt.Errorf("getting pvzs: %w", ErrUnsupportedReceptionStatus)
+	}
+
+	// City may be given by code or any name, repository filters by default name.
+	// Unknown city is kept as is and matches no PVZs.
+	if filter.City != "" {
+		city, err := p.localizer.cities.GetCity(ctx, filter.City)
+		if err == nil {
+			filter.City = city.Name
+		} else if !errors.Is(err, repository.ErrCityNotFound) {
+			return model.PVZPage{}, ErrCannotGetPVZ
+		}
+	}
+
+	after, err := decodeCursor(cursor)
 	if err != nil {
-		return nil, ErrCannotGetPVZ
+		return model.PVZPage{}, fmt.Errorf("getting pvzs: %w", ErrInvalidCursor)
+	}
+
+	// One more PVZ is selected to know if there is the next page.
+	pvzs, err := p.repo.GetPVZPagination(ctx, filter, after, limit+1)
+	if err != nil {
+		return model.PVZPage{}, ErrCannotGetPVZ
+	}
+
+	page := model.PVZPage{
+		Items: p.localizer.pvzs(ctx, pvzs),
+	}
+
+	if len(pvzs) > limit {
+		page.Items = pvzs[:limit]
+
+		last := page.Items[limit-1]
+		page.NextCursor, err = encodeCursor(model.PVZCursor{
+			RegistrationDate: last.RegistrationDate,
+			ID:               last.ID,
+		})
+		if err != nil {
+			return model.PVZPage{}, ErrCannotGetPVZ
+		}
+	}
+
+	if withTotal {
+		total, err := p.repo.CountPVZ(ctx, filter)
+		if err != nil {
+			return model.PVZPage{}, ErrCannotGetPVZ
+		}
+
+		page.Total = &total
 	}
 
-	return pvzs, nil
+	return page, nil
 }
 
 // GetPVZList implements PVZ.
@@ -51,5 +139,94 @@ func (p *PVZService) GetPVZList(ctx context.Context) ([]model.PVZ, error) {
 		return nil, ErrCannotGetPVZ
 	}
 
-	return pvzs, nil
+	return p.localizer.pvzs(ctx, pvzs), nil
+}
+
+// GetPVZ implements PVZ.
+func (p *PVZService) GetPVZ(ctx context.Context, pvzID uuid.UUID) (model.PVZ, error) {
+	pvz, err := p.repo.GetPVZ(ctx, pvzID)
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return model.PVZ{}, fmt.Errorf("getting pvz: %w", ErrPVZNotFound)
+	} else if err != nil {
+		return model.PVZ{}, ErrCannotGetPVZ
+	}
+
+	return p.localizer.pvz(ctx, pvz), nil
+}
+
+// Layout of opening and closing time in working hours.
+const _timeOfDayLayout = "15:04"
+
+// validatePVZ checks details and status of PVZ and normalizes them.
+func validatePVZ(pvz model.PVZ) (model.PVZ, error) {
+	switch pvz.Status {
+	case model.PVZStatusActive, model.PVZStatusSuspended, model.PVZStatusClosed:
+	default:
+		return model.PVZ{}, ErrUnsupportedPVZStatus
+	}
+
+	pvz.Address = strings.TrimSpace(pvz.Address)
+
+	// Negated comparisons reject NaN too.
+	if location := pvz.Location; location != nil &&
+		(!(location.Latitude >= -90 && location.Latitude <= 90) || !(location.Longitude >= -180 && location.Longitude <= 180)) {
+		return model.PVZ{}, ErrInvalidLocation
+	}
+
+	if pvz.Capacity < 0 {
+		return model.PVZ{}, ErrInvalidCapacity
+	}
+
+	workingHours, err := normalizeWorkingHours(pvz.WorkingHours)
+	if err != nil {
+		return model.PVZ{}, err
+	}
+	pvz.WorkingHours = workingHours
+
+	return pvz, nil
+}
+
+// normalizeWorkingHours checks that PVZ opens before it closes on the same day
+// and intervals of one weekday don't overlap. It returns intervals ordered
+// by weekday and opening time with times formatted by _timeOfDayLayout.
+func normalizeWorkingHours(workingHours []model.WorkingHours) ([]model.WorkingHours, error) {
+	if len(workingHours) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]model.WorkingHours, 0, len(workingHours))
+	for _, hours := range workingHours {
+		if hours.Weekday < 1 || hours.Weekday > 7 {
+			return nil, ErrInvalidWorkingHours
+		}
+
+		opens, err := time.Parse(_timeOfDayLayout, hours.Opens)
+		if err != nil {
+			return nil, ErrInvalidWorkingHours
+		}
+
+		closes, err := time.Parse(_timeOfDayLayout, hours.Closes)
+		if err != nil || !opens.Before(closes) {
+			return nil, ErrInvalidWorkingHours
+		}
+
+		normalized = append(normalized, model.WorkingHours{
+			Weekday: hours.Weekday,
+			Opens:   opens.Format(_timeOfDayLayout),
+			Closes:  closes.Format(_timeOfDayLayout),
+		})
+	}
+
+	// Formatted times are ordered the same way as times themselves.
+	slices.SortFunc(normalized, func(a, b model.WorkingHours) int {
+		return cmp.Or(cmp.Compare(a.Weekday, b.Weekday), cmp.Compare(a.Opens, b.Opens))
+	})
+
+	for i := 1; i < len(normalized); i++ {
+		if normalized[i].Weekday == normalized[i-1].Weekday && normalized[i].Opens < normalized[i-1].Closes {
+			return nil, ErrInvalidWorkingHours
+		}
+	}
+
+	return normalized, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -14,35 +15,57 @@ import (
 var _ Reception = (*ReceptionService)(nil)
 
 type ReceptionService struct {
-	repo repository.ReceptionRepository
+	repo   repository.ReceptionRepository
+	events publisher
 }
 
-func newReceptionService(repo repository.ReceptionRepository) *ReceptionService {
+func newReceptionService(repo repository.ReceptionRepository, events publisher) *ReceptionService {
 	return &ReceptionService{
-		repo: repo,
+		repo:   repo,
+		events: events,
 	}
 }
 
 // CloseLastReception implements Reception.
 func (r *ReceptionService) CloseLastReception(ctx context.Context, pvzID uuid.UUID) (model.Reception, error) {
 	reception, err := r.repo.CloseLastReception(ctx, pvzID)
-	if errors.Is(err, repository.ErrNoReceptionInProgress) {
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return model.Reception{}, fmt.Errorf("closing reception: %w", ErrPVZNotFound)
+	} else if errors.Is(err, repository.ErrNoReceptionInProgress) {
 		return model.Reception{}, fmt.Errorf("closing reception: %w", ErrNoReceptionInProgress)
 	} else if err != nil {
 		return model.Reception{}, ErrCannotCloseReception
 	}
 
+	r.events.Publish(model.Event{
+		Type:       model.EventReceptionClosed,
+		PVZID:      pvzID,
+		Reception:  reception,
+		OccurredAt: time.Now(),
+	})
+
 	return reception, nil
 }
 
 // CreateReception implements Reception.
 func (r *ReceptionService) CreateReception(ctx context.Context, pvzID uuid.UUID) (model.Reception, error) {
 	reception, err := r.repo.CreateReception(ctx, pvzID)
-	if errors.Is(err, repository.ErrReceptionInProgress) {
+	if errors.Is(err, repository.ErrPVZNotFound) {
+		return model.Reception{}, fmt.Errorf("creating reception: %w", ErrPVZNotFound)
+	} else if errors.Is(err, repository.ErrPVZNotActive) {
+		return model.Reception{}, fmt.Errorf("creating reception: %w", ErrPVZNotActive)
+	} else if errors.Is(err, repository.ErrReceptionInProgress) {
 		return model.Reception{}, fmt.Errorf("creating reception: %w", ErrReceptionInProgress)
 	} else if err != nil {
 		return model.Reception{}, ErrCannotCreateReception
 	}
 
+	r.events.Publish(model.Event{
+		Type:       model.EventReceptionOpened,
+		PVZID:      pvzID,
+		Reception:  reception,
+		OccurredAt: time.Now(),
+	})
+
 	return reception, nil
 }
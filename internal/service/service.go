@@ -2,24 +2,39 @@ package service
 
 import (
 	"context"
-	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/sudeeya/avito-assignment/internal/config"
+	"github.com/sudeeya/avito-assignment/internal/eventbus"
 	"github.com/sudeeya/avito-assignment/internal/model"
 	"github.com/sudeeya/avito-assignment/internal/repository"
 )
 
 type Auth interface {
-	IssueToken(ctx context.Context) (string, error)
-	VerifyToken(ctx context.Context, token string) error
+	IssueToken(ctx context.Context, identity model.Identity) (string, error)
+	// VerifyToken returns ErrCannotVerifyToken if token can't be checked
+	// for revocation, any other error means that token is invalid.
+	VerifyToken(ctx context.Context, token string) (model.Identity, error)
+	// Register registers employee, users with other roles can be registered
+	// only if identity in context is moderator.
+	Register(ctx context.Context, email, password, role string) (model.User, error)
+	Login(ctx context.Context, email, password string) (model.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (model.TokenPair, error)
+	Revoke(ctx context.Context, identity model.Identity, refreshToken string) error
+	PublicKeys(ctx context.Context) []model.JWK
 }
 
 type PVZ interface {
-	CreatePVZ(ctx context.Context, city string) (model.PVZ, error)
-	GetPVZPagination(ctx context.Context, start, end time.Time, limit, offset int) ([]model.PVZ, error)
+	// CreatePVZ creates PVZ in city given by code or any name, empty status means active.
+	CreatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error)
+	// UpdatePVZ replaces details and status of PVZ. Only active PVZ accepts new receptions.
+	UpdatePVZ(ctx context.Context, pvz model.PVZ) (model.PVZ, error)
+	// GetPVZPagination returns page of PVZs going after cursor, empty cursor means the first page.
+	// Zero limit means the maximum page size. Total is counted only if withTotal is true.
+	GetPVZPagination(ctx context.Context, filter model.PVZFilter, cursor string, limit int, withTotal bool) (model.PVZPage, error)
 	GetPVZList(ctx context.Context) ([]model.PVZ, error)
+	GetPVZ(ctx context.Context, pvzID uuid.UUID) (model.PVZ, error)
 }
 
 type Reception interface {
@@ -28,27 +43,70 @@ type Reception interface {
 }
 
 type Product interface {
-	AddProduct(ctx context.Context, pvzID uuid.UUID, productType string) (model.Product, error)
+	// AddProduct checks product against attributes of its type and adds it.
+	AddProduct(ctx context.Context, pvzID uuid.UUID, product model.NewProduct) (model.Product, error)
+	// AddProducts adds valid products in one transaction and rejects the rest.
+	AddProducts(ctx context.Context, pvzID uuid.UUID, products []model.NewProduct) ([]model.Product, []model.ProductRejection, error)
 	DeleteLastProduct(ctx context.Context, pvzID uuid.UUID) error
+	// DeleteLastProducts undoes count last added products atomically
+	// and returns them, the last added first.
+	DeleteLastProducts(ctx context.Context, pvzID uuid.UUID, count int) ([]model.Product, error)
+}
+
+type ProductType interface {
+	GetProductTypes(ctx context.Context) ([]model.ProductType, error)
+	CreateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error)
+	UpdateProductType(ctx context.Context, productType model.ProductType) (model.ProductType, error)
+	// DeleteProductType deletes product type if there are no products of it.
+	DeleteProductType(ctx context.Context, productTypeID uuid.UUID) error
+}
+
+type City interface {
+	GetCities(ctx context.Context) ([]model.City, error)
+	// CreateCity creates city or activates deactivated one with the same name and code.
+	CreateCity(ctx context.Context, city model.City) (model.City, error)
+	// DeleteCity deactivates city and returns it. With hard it deletes city instead,
+	// which is possible only if there are no PVZs in it.
+	DeleteCity(ctx context.Context, cityID uuid.UUID, hard bool) (model.City, error)
+}
+
+type Events interface {
+	Subscribe(ctx context.Context) <-chan model.Event
+}
+
+type publisher interface {
+	Publish(event model.Event)
 }
 
 type Services struct {
-	Auth      Auth
-	PVZ       PVZ
-	Reception Reception
-	Product   Product
+	Auth        Auth
+	PVZ         PVZ
+	Reception   Reception
+	Product     Product
+	ProductType ProductType
+	City        City
+	Events      Events
 }
 
 func NewService(cfg config.ServerConfig, repo repository.Repository) (*Services, error) {
-	auth, err := newAuthService(cfg)
+	auth, err := newAuthService(cfg, repo, repo)
 	if err != nil {
 		return nil, err
 	}
 
+	bus := eventbus.New()
+	l := localizer{
+		cities:       repo,
+		productTypes: repo,
+	}
+
 	return &Services{
-		Auth:      auth,
-		PVZ:       newPVZService(repo),
-		Reception: newReceptionService(repo),
-		Product:   newProductService(repo),
+		Auth:        auth,
+		PVZ:         newPVZService(repo, l, cfg.ServerMaxPageSize),
+		Reception:   newReceptionService(repo, bus),
+		Product:     newProductService(repo, repo, l, bus),
+		ProductType: newProductTypeService(repo),
+		City:        newCityService(repo),
+		Events:      bus,
 	}, nil
 }
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Products inserted in one transaction share datetime, so insertion order
+-- is kept by sequence to delete them strictly in LIFO order.
+ALTER TABLE products ADD COLUMN seq BIGINT GENERATED ALWAYS AS IDENTITY;
+
+CREATE INDEX idx_products_reception_id_seq ON products(reception_id, seq);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_products_reception_id_seq;
+
+ALTER TABLE products DROP COLUMN seq;
+-- +goose StatementEnd
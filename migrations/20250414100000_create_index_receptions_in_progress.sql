@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- PVZ can have only one reception in progress, even under concurrent requests.
+CREATE UNIQUE INDEX idx_receptions_pvz_id_in_progress ON receptions(pvz_id) WHERE status = 'in_progress';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_receptions_pvz_id_in_progress;
+-- +goose StatementEnd
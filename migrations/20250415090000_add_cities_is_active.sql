@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deactivated cities are kept for existing PVZs, but new PVZs can't be created in them.
+ALTER TABLE cities ADD COLUMN is_active BOOLEAN DEFAULT TRUE NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE cities DROP COLUMN is_active;
+-- +goose StatementEnd
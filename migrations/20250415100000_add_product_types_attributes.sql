@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Weights are in grams, NULL max_weight means no limit.
+ALTER TABLE product_types
+    ADD COLUMN serial_number_required BOOLEAN DEFAULT FALSE NOT NULL,
+    ADD COLUMN max_weight INTEGER CHECK (max_weight > 0);
+
+ALTER TABLE products
+    ADD COLUMN serial_number TEXT,
+    ADD COLUMN weight INTEGER CHECK (weight > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE products
+    DROP COLUMN serial_number,
+    DROP COLUMN weight;
+
+ALTER TABLE product_types
+    DROP COLUMN serial_number_required,
+    DROP COLUMN max_weight;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Replicas cache cities and product types and reload them on notification.
+-- Payload is the name of the changed table.
+CREATE FUNCTION notify_reference_data_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('reference_data_changed', TG_TABLE_NAME);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER cities_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON cities
+FOR EACH STATEMENT EXECUTE FUNCTION notify_reference_data_changed();
+
+CREATE TRIGGER product_types_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON product_types
+FOR EACH STATEMENT EXECUTE FUNCTION notify_reference_data_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER product_types_changed ON product_types;
+DROP TRIGGER cities_changed ON cities;
+DROP FUNCTION notify_reference_data_changed();
+-- +goose StatementEnd
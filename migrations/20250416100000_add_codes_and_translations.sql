@@ -0,0 +1,82 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cities and product types get stable codes for integrations. Column name
+-- keeps the default (Russian) name, translations to other languages are
+-- stored separately.
+ALTER TABLE cities ADD COLUMN code TEXT;
+
+UPDATE cities
+SET code = CASE name
+    WHEN 'Москва' THEN 'msk'
+    WHEN 'Санкт-Петербург' THEN 'spb'
+    WHEN 'Казань' THEN 'kzn'
+    ELSE id::TEXT
+END;
+
+ALTER TABLE cities
+    ALTER COLUMN code SET NOT NULL,
+    ADD CONSTRAINT cities_code_key UNIQUE (code);
+
+ALTER TABLE product_types ADD COLUMN code TEXT;
+
+UPDATE product_types
+SET code = CASE name
+    WHEN 'электроника' THEN 'electronics'
+    WHEN 'одежда' THEN 'clothes'
+    WHEN 'обувь' THEN 'shoes'
+    ELSE id::TEXT
+END;
+
+ALTER TABLE product_types
+    ALTER COLUMN code SET NOT NULL,
+    ADD CONSTRAINT product_types_code_key UNIQUE (code);
+
+CREATE TABLE city_translations (
+    city_id UUID REFERENCES cities(id) ON DELETE CASCADE NOT NULL,
+    language TEXT NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (city_id, language)
+);
+
+CREATE TABLE product_type_translations (
+    product_type_id UUID REFERENCES product_types(id) ON DELETE CASCADE NOT NULL,
+    language TEXT NOT NULL,
+    name TEXT NOT NULL,
+    PRIMARY KEY (product_type_id, language)
+);
+
+INSERT INTO city_translations (city_id, language, name)
+SELECT id, 'en', CASE code
+    WHEN 'msk' THEN 'Moscow'
+    WHEN 'spb' THEN 'Saint Petersburg'
+    WHEN 'kzn' THEN 'Kazan'
+END
+FROM cities
+WHERE code IN ('msk', 'spb', 'kzn');
+
+INSERT INTO product_type_translations (product_type_id, language, name)
+SELECT id, 'en', CASE code
+    WHEN 'electronics' THEN 'electronics'
+    WHEN 'clothes' THEN 'clothes'
+    WHEN 'shoes' THEN 'shoes'
+END
+FROM product_types
+WHERE code IN ('electronics', 'clothes', 'shoes');
+
+CREATE TRIGGER city_translations_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON city_translations
+FOR EACH STATEMENT EXECUTE FUNCTION notify_reference_data_changed();
+
+CREATE TRIGGER product_type_translations_changed
+AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON product_type_translations
+FOR EACH STATEMENT EXECUTE FUNCTION notify_reference_data_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE product_type_translations;
+DROP TABLE city_translations;
+
+ALTER TABLE product_types DROP COLUMN code;
+ALTER TABLE cities DROP COLUMN code;
+-- +goose StatementEnd
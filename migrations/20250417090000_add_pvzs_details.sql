@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Only active PVZ accepts new receptions.
+CREATE TYPE pvz_status AS ENUM ('active', 'suspended', 'closed');
+
+-- Details are optional, so existing PVZs stay valid: address is empty,
+-- location and capacity are NULL and working hours are empty list.
+ALTER TABLE pvzs
+    ADD COLUMN address TEXT DEFAULT '' NOT NULL,
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180),
+    ADD COLUMN working_hours JSONB DEFAULT '[]' NOT NULL,
+    ADD COLUMN capacity INTEGER CHECK (capacity > 0),
+    ADD COLUMN status pvz_status DEFAULT 'active' NOT NULL,
+    ADD CONSTRAINT pvzs_location_check CHECK ((latitude IS NULL) = (longitude IS NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE pvzs
+    DROP COLUMN status,
+    DROP COLUMN capacity,
+    DROP COLUMN working_hours,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude,
+    DROP COLUMN address;
+
+DROP TYPE pvz_status;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cities and product types are looked up by code, name or translation
+-- case insensitive, so codes and names must be unique regardless of case.
+-- Uniqueness across codes, names and translations is checked by repository.
+CREATE UNIQUE INDEX idx_cities_lower_code ON cities (LOWER(code));
+CREATE UNIQUE INDEX idx_cities_lower_name ON cities (LOWER(name));
+CREATE INDEX idx_city_translations_lower_name ON city_translations (LOWER(name));
+
+CREATE UNIQUE INDEX idx_product_types_lower_code ON product_types (LOWER(code));
+CREATE UNIQUE INDEX idx_product_types_lower_name ON product_types (LOWER(name));
+CREATE INDEX idx_product_type_translations_lower_name ON product_type_translations (LOWER(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_product_type_translations_lower_name;
+DROP INDEX idx_product_types_lower_name;
+DROP INDEX idx_product_types_lower_code;
+
+DROP INDEX idx_city_translations_lower_name;
+DROP INDEX idx_cities_lower_name;
+DROP INDEX idx_cities_lower_code;
+-- +goose StatementEnd
@@ -16,27 +16,18 @@ import (
 type IntegrationSuite struct {
 	suite.Suite
 
-	url    string
-	bearer string
-	client *http.Client
+	url             string
+	moderatorBearer string
+	employeeBearer  string
+	client          *http.Client
 }
 
 func (s *IntegrationSuite) SetupSuite() {
 	s.url = "http://localhost:8080/api/v1"
 	s.client = &http.Client{}
 
-	req, err := http.NewRequest(http.MethodPost, s.url+"/dummyLogin", bytes.NewReader(
-		[]byte(`{"role": "moderator"}`),
-	))
-	s.Require().NoError(err, "Failed to create request")
-
-	resp, err := s.client.Do(req)
-	s.Require().NoError(err, "Failed to do request")
-
-	token, err := io.ReadAll(resp.Body)
-	s.Require().NoError(err, "Failed to read token")
-
-	s.bearer = "Bearer " + string(token)
+	s.moderatorBearer = s.dummyLogin("moderator")
+	s.employeeBearer = s.dummyLogin("employee")
 }
 
 func TestIntegrationTestSuite(t *testing.T) {
@@ -50,7 +41,7 @@ func (s *IntegrationSuite) TestCreateAndCloseReception() {
 	))
 	s.Require().NoError(err, "Failed to create request")
 
-	s.addToken(req)
+	s.addToken(req, s.moderatorBearer)
 
 	resp, err := s.client.Do(req)
 	s.Require().NoError(err, "Failed to do request")
@@ -68,7 +59,7 @@ func (s *IntegrationSuite) TestCreateAndCloseReception() {
 	))
 	s.Require().NoError(err, "Failed to create request")
 
-	s.addToken(req)
+	s.addToken(req, s.employeeBearer)
 
 	resp, err = s.client.Do(req)
 	s.Require().NoError(err, "Failed to do request")
@@ -90,7 +81,7 @@ func (s *IntegrationSuite) TestCreateAndCloseReception() {
 		))
 		s.Require().NoError(err, "Failed to create request")
 
-		s.addToken(req)
+		s.addToken(req, s.employeeBearer)
 
 		resp, err = s.client.Do(req)
 		s.Require().NoError(err, "Failed to do request")
@@ -108,7 +99,7 @@ func (s *IntegrationSuite) TestCreateAndCloseReception() {
 	req, err = http.NewRequest(http.MethodPost, s.url+"/pvz/"+pvz.ID.String()+"/close_last_reception", nil)
 	s.Require().NoError(err, "Failed to create request")
 
-	s.addToken(req)
+	s.addToken(req, s.employeeBearer)
 
 	resp, err = s.client.Do(req)
 	s.Require().NoError(err, "Failed to do request")
@@ -122,6 +113,21 @@ func (s *IntegrationSuite) TestCreateAndCloseReception() {
 	s.Require().Equal(reception.PVZID, receptionOnClose.PVZID, "Another PVZ ID was returned")
 }
 
-func (s *IntegrationSuite) addToken(req *http.Request) {
-	req.Header.Set("Authorization", s.bearer)
+func (s *IntegrationSuite) dummyLogin(role string) string {
+	req, err := http.NewRequest(http.MethodPost, s.url+"/dummyLogin", bytes.NewReader(
+		[]byte(`{"role": "`+role+`"}`),
+	))
+	s.Require().NoError(err, "Failed to create request")
+
+	resp, err := s.client.Do(req)
+	s.Require().NoError(err, "Failed to do request")
+
+	token, err := io.ReadAll(resp.Body)
+	s.Require().NoError(err, "Failed to read token")
+
+	return "Bearer " + string(token)
+}
+
+func (s *IntegrationSuite) addToken(req *http.Request, bearer string) {
+	req.Header.Set("Authorization", bearer)
 }